@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"time"
 
 	_ "github.com/rrab-0/its-gram/docs"
 	"github.com/spf13/viper"
@@ -13,7 +14,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rrab-0/its-gram/db"
 	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/digest"
+	"github.com/rrab-0/its-gram/internal/message"
+	"github.com/rrab-0/its-gram/internal/notification"
 	"github.com/rrab-0/its-gram/internal/post"
+	"github.com/rrab-0/its-gram/internal/preference"
+	"github.com/rrab-0/its-gram/internal/push"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"github.com/rrab-0/its-gram/internal/search"
+	"github.com/rrab-0/its-gram/internal/story"
 	"github.com/rrab-0/its-gram/internal/user"
 	"github.com/rrab-0/its-gram/router"
 	"golang.ngrok.com/ngrok"
@@ -55,16 +64,62 @@ func main() {
 		log.Fatalf("ERROR: Failed to initialize firebase auth: %v", err.Error())
 	}
 
-	userHandler := user.NewHandler(pgsql.DB)
-	postHandler := post.NewHandler(pgsql.DB)
+	hub := realtime.NewHub(realtime.NewMemoryBroker())
+	if err := hub.Start(context.Background()); err != nil {
+		log.Fatalf("ERROR: Failed to start realtime hub: %v", err.Error())
+	}
+
+	pushProvider, err := newPushProvider(firebase)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to initialize push provider: %v", err.Error())
+	}
+
+	messageHandler := message.NewHandler(pgsql.DB, hub)
+	preferenceHandler := preference.NewHandler(pgsql.DB)
+	pushHandler := push.NewHandler(pgsql.DB, pushProvider, preferenceHandler.Service)
+	notificationHandler := notification.NewHandler(pgsql.DB, hub, pushHandler.Service, preferenceHandler.Service)
+	userHandler := user.NewHandler(pgsql.DB, notificationHandler.Service)
+	postHandler := post.NewHandler(pgsql.DB, notificationHandler.Service, hub)
+	searchHandler := search.NewHandler(pgsql.DB, userHandler.Service, postHandler.Service)
+	realtimeHandler := realtime.NewHandler(hub, map[string]realtime.TopicAuthorizer{
+		realtime.POST_TOPIC_PREFIX:         postHandler.Service,
+		realtime.CONVERSATION_TOPIC_PREFIX: messageHandler.Service,
+	})
+
+	post.NewJob(postHandler.Service, post.SCHEDULE_INTERVAL).Start(context.Background())
+	post.NewPurgeJob(postHandler.Service, post.PURGE_INTERVAL).Start(context.Background())
+
+	storyHandler := story.NewHandler(pgsql.DB)
+	story.NewJob(storyHandler.Service, story.EXPIRY_INTERVAL).Start(context.Background())
+
+	digestSender, err := newDigestSender()
+	if err != nil {
+		log.Fatalf("ERROR: Failed to initialize digest sender: %v", err.Error())
+	}
+
+	digestHandler := digest.NewHandler(pgsql.DB, digestSender, preferenceHandler.Service, viper.GetString("APP_URL"), viper.GetString("DIGEST_SECRET"))
+	if digestSender != nil {
+		digest.NewJob(digestHandler.Service, viper.GetDuration("DIGEST_CHECK_INTERVAL")).Start(context.Background())
+	} else {
+		log.Printf("WARNING: SMTP_HOST is not set, digest emails are disabled")
+	}
 
 	gin.ForceConsoleColor()
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.LoggerWithFormatter(redactedLogFormatter), gin.Recovery())
 	router.Setup(
 		r,
 		firebaseAuth,
 		userHandler,
 		postHandler,
+		searchHandler,
+		notificationHandler,
+		realtimeHandler,
+		pushHandler,
+		preferenceHandler,
+		digestHandler,
+		messageHandler,
+		storyHandler,
 	)
 
 	if err := runServer(context.Background(), r); err != nil {
@@ -72,6 +127,70 @@ func main() {
 	}
 }
 
+// Query values that can't end up in access logs, the WebSocket's ID token and digest unsubscribe tokens.
+var redactedQueryKeys = []string{"token"}
+
+// Same line as gin's default logger, with redactedQueryKeys replaced in the path.
+func redactedLogFormatter(param gin.LogFormatterParams) string {
+	query := param.Request.URL.Query()
+	redacted := false
+	for _, key := range redactedQueryKeys {
+		if query.Has(key) {
+			query.Set(key, "REDACTED")
+			redacted = true
+		}
+	}
+
+	if redacted {
+		param.Path = param.Request.URL.Path + "?" + query.Encode()
+	}
+
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
+
+// FCM unless "PUSH_PROVIDER" says otherwise, fake one only logs the pushes.
+func newPushProvider(firebase *internal.FirebaseApp) (push.Provider, error) {
+	if viper.GetString("PUSH_PROVIDER") == push.PROVIDER_FAKE {
+		return push.NewFakeProvider(), nil
+	}
+
+	return push.NewFCMProvider(firebase.App)
+}
+
+// Nil when SMTP isn't configured.
+func newDigestSender() (digest.Sender, error) {
+	if viper.GetString("SMTP_HOST") == "" {
+		return nil, nil
+	}
+
+	return digest.NewSMTPSender(digest.SMTPConfig{
+		Host:     viper.GetString("SMTP_HOST"),
+		Port:     viper.GetString("SMTP_PORT"),
+		Username: viper.GetString("SMTP_USERNAME"),
+		Password: viper.GetString("SMTP_PASSWORD"),
+		From:     viper.GetString("SMTP_FROM"),
+	})
+}
+
 func runServer(ctx context.Context, r *gin.Engine) error {
 	env := viper.GetString("ENV")
 
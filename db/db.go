@@ -49,20 +49,72 @@ func NewPostgreSQL() (postgreSQL, error) {
 		return postgreSQL{}, err
 	}
 
+	if err := setupJoinTables(db); err != nil {
+		return postgreSQL{}, err
+	}
+
 	log.Println("SUCCESS: Connected to PostgreSQL database.")
 	return postgreSQL{DB: db}, nil
 }
 
+// Likes are reactions, their join tables have the reaction on top of the two ids.
+// Follows keep when they happened.
+func setupJoinTables(db *gorm.DB) error {
+	joinTables := []struct {
+		model     interface{}
+		field     string
+		joinTable interface{}
+	}{
+		{&internal.User{}, "LikedPosts", &internal.PostReaction{}},
+		{&internal.Post{}, "Likes", &internal.PostReaction{}},
+		{&internal.User{}, "LikedComments", &internal.CommentReaction{}},
+		{&internal.Comment{}, "Likes", &internal.CommentReaction{}},
+		{&internal.User{}, "Followings", &internal.UserFollowing{}},
+	}
+
+	for _, joinTable := range joinTables {
+		if err := db.SetupJoinTable(joinTable.model, joinTable.field, joinTable.joinTable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (p postgreSQL) Migrate() error {
 	err := p.DB.AutoMigrate(
 		internal.Comment{},
 		internal.Post{},
 		internal.User{},
+		// AutoMigrate only creates missing join tables, these add the extra columns to existing ones
+		internal.PostReaction{},
+		internal.CommentReaction{},
+		internal.UserFollowing{},
+		internal.DismissedSuggestion{},
+		internal.RecentSearch{},
+		internal.PostHashtag{},
+		internal.Bookmark{},
+		internal.BookmarkCollection{},
+		internal.BookmarkCollectionPost{},
+		internal.Notification{},
+		internal.DeviceToken{},
+		internal.NotificationPreference{},
+		internal.DigestSubscription{},
+		internal.Conversation{},
+		internal.ConversationParticipant{},
+		internal.Message{},
+		internal.Story{},
+		internal.StoryView{},
+		internal.Highlight{},
 	)
 	if err != nil {
 		return err
 	}
 
+	if err := p.migrateRaw(); err != nil {
+		return err
+	}
+
 	log.Println("SUCCESS: PostgreSQL migration completed (Some tables won't be created if they already exist but new fields will be appended).")
 	return nil
 }
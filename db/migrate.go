@@ -1 +1,45 @@
 package db
+
+// Statements AutoMigrate can't express (generated columns, extensions, special indexes),
+// every statement has to be safe to run on every startup.
+var rawMigrations = []string{
+	// Full-text search over posts' title and description
+	`ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, ''))) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (search_vector)`,
+
+	// Fuzzy, case-insensitive search over users' username
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (lower(username) gin_trgm_ops)`,
+
+	// A user reposts a post only once, duplicates from before the index are moved to the trash
+	`UPDATE posts SET deleted_at = now()
+		WHERE type = 'repost' AND deleted_at IS NULL
+		AND EXISTS (
+			SELECT 1 FROM posts AS earlier
+			WHERE earlier.type = 'repost' AND earlier.deleted_at IS NULL
+			AND earlier.user_id = posts.user_id AND earlier.repost_of_id = posts.repost_of_id
+			AND (earlier.created_at, earlier.id) < (posts.created_at, posts.id)
+		)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_user_repost ON posts (user_id, repost_of_id)
+		WHERE type = 'repost' AND deleted_at IS NULL`,
+
+	// Prefix search over hashtags, the primary key's index can't serve LIKE outside of the C collation
+	`CREATE INDEX IF NOT EXISTS idx_post_hashtags_name_pattern ON post_hashtags (name text_pattern_ops)`,
+	// Hashtags of posts from before post_hashtags existed, skipped once it has rows
+	`INSERT INTO post_hashtags (post_id, name)
+		SELECT DISTINCT posts.id, lower(tags.tag[1])
+		FROM posts, regexp_matches(posts.title || ' ' || coalesce(posts.description, ''), '#(\w+)', 'g') AS tags(tag)
+		WHERE NOT EXISTS (SELECT 1 FROM post_hashtags)
+		ON CONFLICT DO NOTHING`,
+}
+
+func (p postgreSQL) migrateRaw() error {
+	for _, stmt := range rawMigrations {
+		if err := p.DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
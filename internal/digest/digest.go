@@ -0,0 +1,69 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+)
+
+const (
+	DIGEST_INTERVAL       = 7 * 24 * time.Hour
+	MAXIMUM_NEW_FOLLOWERS = 5 // Named in the email, the rest are counted
+	MAXIMUM_TOP_POSTS     = 5
+	BATCH_SIZE            = 100
+)
+
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+	Headers map[string]string
+}
+
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// Decides whether a user wants emails of a notification type, implemented by preference.Service.
+type PreferenceChecker interface {
+	AllowsEmail(ctx context.Context, userId, notificationType string) (bool, error)
+}
+
+// Unseen activity of a user since the last digest.
+type Digest struct {
+	User              internal.User
+	NewFollowers      []internal.User
+	OtherNewFollowers int
+	TopPosts          []internal.Post
+	UnsubscribeLink   string
+}
+
+type ClaimDigestQueryRes struct {
+	LastSentAt *time.Time
+}
+
+type UnsubscribeQueryRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type Repository interface {
+	GetDueUserIds(ctx context.Context, dueBefore time.Time, afterUserId string, limit int) ([]string, error)
+	// Marks the digest as sent and returns when the previous one was,
+	// false if another instance already did or the user unsubscribed
+	ClaimDigest(ctx context.Context, userId string, dueBefore, now time.Time) (claimed bool, lastSentAt *time.Time, err error)
+	GetUser(ctx context.Context, userId string) (internal.User, error)
+	GetNewFollowerIds(ctx context.Context, userId string, since time.Time) ([]string, error)
+	GetUsers(ctx context.Context, userIds []string) ([]internal.User, error)
+	GetTopPostsFromFollowings(ctx context.Context, userId string, since time.Time, limit int) ([]internal.Post, error)
+	Unsubscribe(ctx context.Context, userId string) error
+}
+
+type Service interface {
+	// Sends digests to every user that is due one
+	SendDigests(ctx context.Context) error
+	Unsubscribe(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery UnsubscribeQueryRequest) error
+}
@@ -0,0 +1,63 @@
+package digest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(db *gorm.DB, sender Sender, preferences PreferenceChecker, appUrl, secret string) Handler {
+	return Handler{
+		Service: NewService(NewRepository(db), sender, preferences, appUrl, secret),
+	}
+}
+
+// Opened from the email, so authenticated by the signed token instead of firebase.
+func (h Handler) Unsubscribe(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery UnsubscribeQueryRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := h.Service.Unsubscribe(ctx.Request.Context(), reqUri, reqQuery); err != nil {
+		if err == ErrInvalidToken {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, internal.ErrorResponse{
+				Message: "Failed to unsubscribe from digest emails.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to unsubscribe from digest emails.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Unsubscribed from digest emails successfully.",
+	})
+}
@@ -0,0 +1,32 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+)
+
+// Periodically sends digests that are due, safe to run on several instances.
+type Job struct {
+	service  Service
+	interval time.Duration
+}
+
+// How often due digests are checked when no interval is configured.
+const DEFAULT_CHECK_INTERVAL = time.Hour
+
+func NewJob(service Service, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = DEFAULT_CHECK_INTERVAL
+	}
+
+	return Job{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j Job) Start(ctx context.Context) {
+	internal.RunEvery(ctx, j.interval, "send digests", j.service.SendDigests)
+}
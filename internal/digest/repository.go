@@ -0,0 +1,153 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+func (r gormRepository) GetDueUserIds(ctx context.Context, dueBefore time.Time, afterUserId string, limit int) ([]string, error) {
+	var userIds []string
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.User{}).
+		Joins("LEFT JOIN digest_subscriptions ON digest_subscriptions.user_id = users.id").
+		Where("users.email <> '' AND users.id > ?", afterUserId).
+		Where("digest_subscriptions.unsubscribed_at IS NULL").
+		Where("digest_subscriptions.last_sent_at IS NULL OR digest_subscriptions.last_sent_at < ?", dueBefore).
+		Order("users.id").
+		Limit(limit).
+		Pluck("users.id", &userIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+// The previous last_sent_at comes from a CTE, it sees the row as it was before the upsert.
+func (r gormRepository) ClaimDigest(ctx context.Context, userId string, dueBefore, now time.Time) (bool, *time.Time, error) {
+	var claims []ClaimDigestQueryRes
+
+	err := r.db.WithContext(ctx).Raw(`
+	WITH previous AS (
+		SELECT last_sent_at FROM digest_subscriptions WHERE user_id = @userId
+	)
+	INSERT INTO digest_subscriptions (user_id, created_at, updated_at, last_sent_at)
+	VALUES (@userId, @now, @now, @now)
+	ON CONFLICT (user_id) DO UPDATE SET last_sent_at = excluded.last_sent_at, updated_at = excluded.updated_at
+	WHERE digest_subscriptions.unsubscribed_at IS NULL
+	AND (digest_subscriptions.last_sent_at IS NULL OR digest_subscriptions.last_sent_at < @dueBefore)
+	RETURNING (SELECT last_sent_at FROM previous) AS last_sent_at
+	`, map[string]interface{}{
+		"userId":    userId,
+		"now":       now,
+		"dueBefore": dueBefore,
+	}).Scan(&claims).Error
+	if err != nil {
+		return false, nil, err
+	}
+
+	if len(claims) == 0 {
+		return false, nil, nil
+	}
+
+	return true, claims[0].LastSentAt, nil
+}
+
+func (r gormRepository) GetUser(ctx context.Context, userId string) (internal.User, error) {
+	var user internal.User
+
+	if err := r.db.WithContext(ctx).Where("id = ?", userId).First(&user).Error; err != nil {
+		return internal.User{}, err
+	}
+
+	return user, nil
+}
+
+// Users who followed "user" since "since" and still do, latest first.
+func (r gormRepository) GetNewFollowerIds(ctx context.Context, userId string, since time.Time) ([]string, error) {
+	var followerIds []string
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.UserFollowing{}).
+		Joins("JOIN users ON users.id = user_followings.user_id AND users.deleted_at IS NULL").
+		Where("user_followings.following_id = ? AND user_followings.created_at >= ?", userId, since).
+		Order("user_followings.created_at DESC").
+		Pluck("user_followings.user_id", &followerIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return followerIds, nil
+}
+
+func (r gormRepository) GetUsers(ctx context.Context, userIds []string) ([]internal.User, error) {
+	var users []internal.User
+
+	if len(userIds) == 0 {
+		return users, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r gormRepository) GetTopPostsFromFollowings(ctx context.Context, userId string, since time.Time, limit int) ([]internal.Post, error) {
+	var posts []internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Where("user_id IN (SELECT following_id FROM user_followings WHERE user_followings.user_id = ?)", userId).
+		Where("created_at >= ? AND status = ?", since, internal.POST_STATUS_PUBLISHED).
+		Order("(SELECT COUNT(*) FROM user_liked_posts WHERE user_liked_posts.post_id = posts.id) DESC").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r gormRepository) Unsubscribe(ctx context.Context, userId string) error {
+	now := time.Now()
+
+	return r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"unsubscribed_at": now,
+				"updated_at":      now,
+			}),
+		}).
+		Create(&internal.DigestSubscription{
+			UserID:         userId,
+			UnsubscribedAt: &now,
+		}).
+		Error
+}
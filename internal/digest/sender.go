@@ -0,0 +1,84 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string // e.g. "its-gram <no-reply@its-gram.com>"
+}
+
+type smtpSender struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+func NewSMTPSender(config SMTPConfig) (Sender, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return smtpSender{
+		config: config,
+		from:   from,
+	}, nil
+}
+
+func (s smtpSender) Send(ctx context.Context, email Email) error {
+	// Local sinks like mailpit don't need auth
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	return smtp.SendMail(
+		net.JoinHostPort(s.config.Host, s.config.Port),
+		auth,
+		s.from.Address,
+		[]string{email.To},
+		s.message(email),
+	)
+}
+
+func (s smtpSender) message(email Email) []byte {
+	headers := map[string]string{
+		"From":                      s.from.String(),
+		"To":                        email.To,
+		"Date":                      time.Now().Format(time.RFC1123Z),
+		"Subject":                   mime.QEncoding.Encode("UTF-8", email.Subject),
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding": "8bit",
+	}
+	for key, value := range email.Headers {
+		headers[key] = value
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var msg strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, headers[key])
+	}
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+
+	return []byte(msg.String())
+}
@@ -0,0 +1,187 @@
+package digest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/notification"
+)
+
+var bodyTemplate = template.Must(template.New("digest").Parse(`Hi {{.User.Username}},
+
+Here's what you missed on its-gram this week.
+{{if .NewFollowers}}
+New followers:
+{{range .NewFollowers}}- {{.Username}}
+{{end}}{{if .OtherNewFollowers}}...and {{.OtherNewFollowers}} more
+{{end}}{{end}}{{if .TopPosts}}
+Top posts from people you follow:
+{{range .TopPosts}}- "{{.Title}}" by {{.CreatedBy.Username}}
+{{end}}{{end}}
+Don't want these emails anymore? Unsubscribe here: {{.UnsubscribeLink}}
+`))
+
+type digestService struct {
+	repo        Repository
+	sender      Sender
+	preferences PreferenceChecker
+	appUrl      string
+	secret      []byte
+}
+
+// appUrl is the public url of this api, used for unsubscribe links signed with secret.
+func NewService(repo Repository, sender Sender, preferences PreferenceChecker, appUrl, secret string) Service {
+	return digestService{
+		repo:        repo,
+		sender:      sender,
+		preferences: preferences,
+		appUrl:      strings.TrimSuffix(appUrl, "/"),
+		secret:      []byte(secret),
+	}
+}
+
+func (s digestService) SendDigests(ctx context.Context) error {
+	var (
+		now         = time.Now()
+		dueBefore   = now.Add(-DIGEST_INTERVAL)
+		afterUserId string
+	)
+
+	for {
+		userIds, err := s.repo.GetDueUserIds(ctx, dueBefore, afterUserId, BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, userId := range userIds {
+			// One user failing shouldn't stop everyone else's digest
+			if err := s.sendDigest(ctx, userId, dueBefore, now); err != nil {
+				log.Printf("ERROR: Failed to send digest to user %v: %v", userId, err.Error())
+			}
+		}
+
+		if len(userIds) < BATCH_SIZE {
+			return nil
+		}
+		afterUserId = userIds[len(userIds)-1]
+	}
+}
+
+// Claimed before sending, a failed send skips the user until the next interval instead of sending twice.
+func (s digestService) sendDigest(ctx context.Context, userId string, dueBefore, now time.Time) error {
+	claimed, lastSentAt, err := s.repo.ClaimDigest(ctx, userId, dueBefore, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	// Follows since the previous digest, the first one covers the last interval
+	followedSince := dueBefore
+	if lastSentAt != nil {
+		followedSince = *lastSentAt
+	}
+
+	digest, err := s.buildDigest(ctx, userId, followedSince, dueBefore)
+	if err != nil {
+		return err
+	}
+
+	// Nothing they haven't seen
+	if len(digest.NewFollowers) == 0 && len(digest.TopPosts) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	if err := bodyTemplate.Execute(&body, digest); err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, Email{
+		To:      digest.User.Email,
+		Subject: "Your weekly its-gram digest",
+		Body:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      fmt.Sprintf("<%s>", digest.UnsubscribeLink),
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+func (s digestService) buildDigest(ctx context.Context, userId string, followedSince, since time.Time) (Digest, error) {
+	user, err := s.repo.GetUser(ctx, userId)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	digest := Digest{
+		User:            user,
+		UnsubscribeLink: s.unsubscribeLink(userId),
+	}
+
+	allowsFollows, err := s.preferences.AllowsEmail(ctx, userId, notification.TYPE_FOLLOW)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	if allowsFollows {
+		followerIds, err := s.repo.GetNewFollowerIds(ctx, userId, followedSince)
+		if err != nil {
+			return Digest{}, err
+		}
+
+		if len(followerIds) > MAXIMUM_NEW_FOLLOWERS {
+			digest.OtherNewFollowers = len(followerIds) - MAXIMUM_NEW_FOLLOWERS
+			followerIds = followerIds[:MAXIMUM_NEW_FOLLOWERS]
+		}
+
+		followers, err := s.repo.GetUsers(ctx, followerIds)
+		if err != nil {
+			return Digest{}, err
+		}
+
+		// Keep latest first
+		followersById := make(map[string]internal.User, len(followers))
+		for _, follower := range followers {
+			followersById[follower.ID] = follower
+		}
+		for _, followerId := range followerIds {
+			if follower, ok := followersById[followerId]; ok {
+				digest.NewFollowers = append(digest.NewFollowers, follower)
+			}
+		}
+	}
+
+	digest.TopPosts, err = s.repo.GetTopPostsFromFollowings(ctx, userId, since, MAXIMUM_TOP_POSTS)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	return digest, nil
+}
+
+func (s digestService) unsubscribeToken(userId string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(userId))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s digestService) unsubscribeLink(userId string) string {
+	return fmt.Sprintf("%s/api/v1/digest/unsubscribe/%s?token=%s", s.appUrl, url.PathEscape(userId), s.unsubscribeToken(userId))
+}
+
+func (s digestService) Unsubscribe(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery UnsubscribeQueryRequest) error {
+	// Links can't be verified without a secret
+	if len(s.secret) == 0 || !hmac.Equal([]byte(reqQuery.Token), []byte(s.unsubscribeToken(reqUri.UserId))) {
+		return ErrInvalidToken
+	}
+
+	return s.repo.Unsubscribe(ctx, reqUri.UserId)
+}
@@ -145,6 +145,70 @@ func (f *FirebaseAuth) ValidateNgrokDevToken(funcType string) gin.HandlerFunc {
 	}
 }
 
+// Same as ValidateToken but also accepts the token from the "token" query,
+// browsers can't set headers when opening a WebSocket.
+func (f *FirebaseAuth) ValidateWebSocketToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if fields := strings.Fields(ctx.GetHeader("Authorization")); len(fields) == 2 {
+			token = fields[1]
+		}
+
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "Failed to authenticate.",
+				Error:   "token is empty",
+			})
+			return
+		}
+
+		idToken, err := f.auth.VerifyIDToken(ctx, token)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "Failed to authenticate.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.Set("user_id", idToken.Claims["user_id"])
+		ctx.Next()
+	}
+}
+
+// Same as ValidateToken but lets requests without a token through anonymously,
+// for public routes that behave differently for signed in users.
+func (f *FirebaseAuth) ValidateOptionalToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenStr := ctx.GetHeader("Authorization")
+		if tokenStr == "" {
+			ctx.Next()
+			return
+		}
+
+		fields := strings.Fields(tokenStr)
+		if len(fields) != 2 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "Failed to authenticate.",
+				Error:   "invalid token format",
+			})
+			return
+		}
+
+		idToken, err := f.auth.VerifyIDToken(ctx, fields[1])
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "Failed to authenticate.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.Set("user_id", idToken.Claims["user_id"])
+		ctx.Next()
+	}
+}
+
 var dummyUserCount = 1
 
 // Checks if userId (doesn't have to be valid) is present in URI request or not,
@@ -182,3 +246,13 @@ func (f *FirebaseAuth) ValidateDevToken(funcType string) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// Dev counterpart of ValidateOptionalToken, uses "user_id" query (doesn't have to be valid) if present.
+func (f *FirebaseAuth) ValidateOptionalDevToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if id := ctx.Query("user_id"); id != "" {
+			ctx.Set("user_id", id)
+		}
+		ctx.Next()
+	}
+}
@@ -38,6 +38,18 @@ func GenerateRequestValidatorError(err error) error {
 		return fmt.Errorf("%s is not a valid email address", fieldName)
 	}
 
+	if tag == "min" {
+		return fmt.Errorf("%s must be at least %s characters long", fieldName, validationErr.Param())
+	}
+
+	if tag == "max" {
+		return fmt.Errorf("%s must be at most %s characters long", fieldName, validationErr.Param())
+	}
+
+	if tag == "oneof" {
+		return fmt.Errorf("%s must be one of %s", fieldName, validationErr.Param())
+	}
+
 	if tag == "uuid" {
 		return fmt.Errorf("%v is not a valid uuid", val)
 	}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Runs run in the background right away then every interval until ctx is done,
+// errors are logged as "Failed to <name>".
+func RunEvery(ctx context.Context, interval time.Duration, name string, run func(context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := run(ctx); err != nil {
+				log.Printf("ERROR: Failed to %v: %v", name, err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,819 @@
+package message
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(db *gorm.DB, publisher realtime.Publisher) Handler {
+	return Handler{
+		Service: NewService(NewRepository(db), publisher),
+	}
+}
+
+const (
+	MAXIMUM_LIMIT = 50
+	MINIMUM_LIMIT = 10
+	MINIMUM_PAGE  = 1
+)
+
+func (h Handler) CreateConversation(ctx *gin.Context) {
+	var (
+		reqUri  internal.UserIdUriRequest
+		reqBody CreateConversationRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to create conversation.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to create conversation.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.CreateConversation(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == ErrSelfConversation {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to create conversation.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to create conversation, user not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to create conversation.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Conversation created successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) GetConversations(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetConversationsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's conversations.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's conversations.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversations, err := h.Service.GetConversations(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's conversations.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's conversations fetched successfully.",
+		Data:    conversations,
+	})
+}
+
+func (h Handler) GetUnreadCount(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's unread messages count.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's unread messages count.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	count, err := h.Service.GetUnreadCount(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's unread messages count.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's unread messages count fetched successfully.",
+		Data:    count,
+	})
+}
+
+func (h Handler) SendMessage(ctx *gin.Context) {
+	var (
+		reqUri  ConversationUriRequest
+		reqBody SendMessageRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to send message.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to send message.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	message, err := h.Service.SendMessage(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == ErrEmptyMessage {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to send message.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == ErrPostNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to send message, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to send message, conversation not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to send message.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Message sent successfully.",
+		Data:    message,
+	})
+}
+
+func (h Handler) GetMessages(ctx *gin.Context) {
+	var (
+		reqUri   ConversationUriRequest
+		reqQuery GetMessagesQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch messages.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch messages.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	messages, err := h.Service.GetMessages(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		if err == ErrInvalidCursor {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Invalid request.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to fetch messages, conversation not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch messages.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Messages fetched successfully.",
+		Data:    messages,
+	})
+}
+
+func (h Handler) DeleteMessage(ctx *gin.Context) {
+	var reqUri MessageUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to delete message.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to delete message.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.DeleteMessage(ctx.Request.Context(), reqUri); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to delete message, message not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to delete message.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Message deleted successfully.",
+	})
+}
+
+// Status code of errors shared by the conversation and group endpoints.
+func groupErrorStatus(err error) int {
+	switch err {
+	case gorm.ErrRecordNotFound, ErrParticipantNotFound, ErrUsersNotFound:
+		return http.StatusNotFound
+	case ErrNotAdmin:
+		return http.StatusForbidden
+	case ErrNotGroup, ErrTooManyParticipants, ErrRemoveSelf, ErrChangeOwnRole:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func (h Handler) GetConversation(ctx *gin.Context) {
+	var reqUri ConversationUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch conversation.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch conversation.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.GetConversation(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to fetch conversation.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Conversation fetched successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) CreateGroup(ctx *gin.Context) {
+	var (
+		reqUri  internal.UserIdUriRequest
+		reqBody CreateGroupRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to create group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to create group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.CreateGroup(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to create group.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Group created successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) UpdateGroup(ctx *gin.Context) {
+	var (
+		reqUri  ConversationUriRequest
+		reqBody UpdateGroupRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to update group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to update group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.UpdateGroup(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to update group.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Group updated successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) AddParticipants(ctx *gin.Context) {
+	var (
+		reqUri  ConversationUriRequest
+		reqBody AddParticipantsRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to add participants to group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to add participants to group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.AddParticipants(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to add participants to group.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Participants added to group successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) RemoveParticipant(ctx *gin.Context) {
+	var reqUri ParticipantUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to remove participant from group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to remove participant from group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.RemoveParticipant(ctx.Request.Context(), reqUri); err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to remove participant from group.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Participant removed from group successfully.",
+	})
+}
+
+func (h Handler) LeaveGroup(ctx *gin.Context) {
+	var reqUri ConversationUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to leave group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to leave group.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.LeaveGroup(ctx.Request.Context(), reqUri); err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to leave group.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Left group successfully.",
+	})
+}
+
+func (h Handler) UpdateRole(ctx *gin.Context) {
+	var (
+		reqUri  ParticipantUriRequest
+		reqBody UpdateRoleRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to update participant's role.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to update participant's role.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	conversation, err := h.Service.UpdateRole(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(groupErrorStatus(err), internal.ErrorResponse{
+			Message: "Failed to update participant's role.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Participant's role updated successfully.",
+		Data:    conversation,
+	})
+}
+
+func (h Handler) MarkAsRead(ctx *gin.Context) {
+	var (
+		reqUri  ConversationUriRequest
+		reqBody MarkAsReadRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	// Body is optional
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+				Message: "Invalid request.",
+				Error:   internal.GenerateRequestValidatorError(err).Error(),
+			})
+			return
+		}
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to mark conversation as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to mark conversation as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.MarkAsRead(ctx.Request.Context(), reqUri, reqBody); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to mark conversation as read, conversation or message not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to mark conversation as read.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Conversation marked as read successfully.",
+	})
+}
@@ -0,0 +1,217 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+const (
+	TYPE_TEXT       = "text"
+	TYPE_POST_SHARE = "post_share" // Body is an optional caption
+
+	// System messages of groups, SenderID is who did it
+	TYPE_GROUP_CREATED  = "group_created"
+	TYPE_GROUP_UPDATED  = "group_updated"
+	TYPE_MEMBER_ADDED   = "member_added"   // TargetUserID is the new member
+	TYPE_MEMBER_REMOVED = "member_removed" // TargetUserID is the removed member
+	TYPE_MEMBER_LEFT    = "member_left"
+	TYPE_ROLE_UPDATED   = "role_updated" // TargetUserID got the role in Body
+)
+
+// Types users send themselves, the rest are system messages.
+var USER_TYPES = []string{TYPE_TEXT, TYPE_POST_SHARE}
+
+const (
+	ROLE_ADMIN  = "admin"
+	ROLE_MEMBER = "member"
+)
+
+const MAXIMUM_GROUP_PARTICIPANTS = 50
+
+var (
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrSelfConversation    = errors.New("can't start a conversation with yourself")
+	ErrNotGroup            = errors.New("conversation is not a group")
+	ErrNotAdmin            = errors.New("only group admins can do this")
+	ErrTooManyParticipants = errors.New("group has too many participants")
+	ErrRemoveSelf          = errors.New("use leave to remove yourself from a group")
+	ErrChangeOwnRole       = errors.New("can't change your own role")
+	ErrParticipantNotFound = errors.New("user is not a participant")
+	ErrUsersNotFound       = errors.New("some users don't exist")
+	ErrEmptyMessage        = errors.New("message needs a body or a post")
+	ErrPostNotFound        = errors.New("post not found")
+)
+
+type ConversationUriRequest struct {
+	UserId         string `uri:"id" binding:"required"`
+	ConversationId string `uri:"conversationId" binding:"required,uuid"`
+}
+
+type MessageUriRequest struct {
+	UserId         string `uri:"id" binding:"required"`
+	ConversationId string `uri:"conversationId" binding:"required,uuid"`
+	MessageId      string `uri:"messageId" binding:"required,uuid"`
+}
+
+type ParticipantUriRequest struct {
+	UserId         string `uri:"id" binding:"required"`
+	ConversationId string `uri:"conversationId" binding:"required,uuid"`
+	OtherUserId    string `uri:"otherUserId" binding:"required"`
+}
+
+type CreateConversationRequest struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type CreateGroupRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	PictureLink string   `json:"picture_link"`
+	UserIds     []string `json:"user_ids" binding:"required,min=1"` // Without the creator
+}
+
+// Fields left empty are unchanged.
+type UpdateGroupRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	PictureLink *string `json:"picture_link"`
+}
+
+type AddParticipantsRequest struct {
+	UserIds []string `json:"user_ids" binding:"required,min=1"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+type GetConversationsQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// Body, PostId or both.
+type SendMessageRequest struct {
+	Body   string `json:"body" binding:"max=2000"`
+	PostId string `json:"post_id" binding:"omitempty,uuid"`
+}
+
+// Marks everything as read when MessageId is empty.
+type MarkAsReadRequest struct {
+	MessageId string `json:"message_id" binding:"omitempty,uuid"`
+}
+
+type GetMessagesQueryRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type MessagesCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type UnreadCountQueryRes struct {
+	ConversationID uuid.UUID
+	UnreadCount    int
+}
+
+type PostCardQueryRes struct {
+	ID            uuid.UUID
+	TotalLikes    int
+	TotalComments int
+}
+
+// Shared post embedded in a message.
+type PostCardResponse struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	CreatedBy     internal.User `json:"created_by"`
+	PictureLink   string        `json:"picture_link"`
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	TotalLikes    int           `json:"total_likes"`
+	TotalComments int           `json:"total_comments"`
+}
+
+type MessageResponse struct {
+	internal.Message
+	// PostCardResponse, or internal.DeletedCommentOrPostResponse once the post is deleted or unpublished
+	Post any `json:"post,omitempty"`
+}
+
+type ConversationResponse struct {
+	ID            uuid.UUID        `json:"id"`
+	IsGroup       bool             `json:"is_group"`
+	Name          string           `json:"name"`
+	PictureLink   string           `json:"picture_link"`
+	Participants  []internal.User  `json:"participants"` // Without the requesting user
+	LastMessage   *MessageResponse `json:"last_message"`
+	LastMessageAt time.Time        `json:"last_message_at"`
+	UnreadCount   int              `json:"unread_count"`
+}
+
+type GetMessagesResponse struct {
+	NextCursor string            `json:"next_cursor"` // Empty when there are no older messages
+	Messages   []MessageResponse `json:"messages"`    // Latest first
+}
+
+type UnreadCountResponse struct {
+	UnreadConversations int `json:"unread_conversations"`
+	UnreadMessages      int `json:"unread_messages"`
+}
+
+type Repository interface {
+	UserExists(ctx context.Context, userId string) (bool, error)
+	GetExistingUserIds(ctx context.Context, userIds []string) ([]string, error)
+	GetOrCreateDirectConversation(ctx context.Context, directKey string, userIds []string) (internal.Conversation, error)
+	CreateGroup(ctx context.Context, conversation internal.Conversation, participants []internal.ConversationParticipant, systemMessage internal.Message) (internal.Conversation, []internal.Message, error)
+	GetConversation(ctx context.Context, conversationId uuid.UUID) (internal.Conversation, error)
+	// Membership changes are stored together with their system messages
+	UpdateGroup(ctx context.Context, conversationId uuid.UUID, updates map[string]interface{}, systemMessage internal.Message) ([]internal.Message, error)
+	AddParticipants(ctx context.Context, participants []internal.ConversationParticipant, systemMessages []internal.Message) ([]internal.Message, error)
+	// Promotes the earliest member when the last admin is removed
+	RemoveParticipant(ctx context.Context, conversationId uuid.UUID, userId string, systemMessage internal.Message) ([]internal.Message, error)
+	UpdateRole(ctx context.Context, conversationId uuid.UUID, userId, role string, systemMessage internal.Message) ([]internal.Message, error)
+	GetConversations(ctx context.Context, userId string, page, limit int) ([]internal.Conversation, error)
+	GetLastMessages(ctx context.Context, conversationIds []uuid.UUID) ([]internal.Message, error)
+	// Per conversation, all of the user's conversations when conversationIds is empty
+	GetUnreadCounts(ctx context.Context, userId string, conversationIds []uuid.UUID) ([]UnreadCountQueryRes, error)
+	GetParticipant(ctx context.Context, conversationId uuid.UUID, userId string) (internal.ConversationParticipant, error)
+	GetParticipantIds(ctx context.Context, conversationId uuid.UUID) ([]string, error)
+	// False if the marker was already at or after readAt
+	MarkAsRead(ctx context.Context, conversationId uuid.UUID, userId string, readAt time.Time) (bool, error)
+	GetMessage(ctx context.Context, conversationId, messageId uuid.UUID) (internal.Message, error)
+
+	CreateMessage(ctx context.Context, message internal.Message) (internal.Message, error)
+	GetMessages(ctx context.Context, conversationId uuid.UUID, cursor *MessagesCursor, limit int) ([]internal.Message, error)
+	DeleteMessage(ctx context.Context, conversationId, messageId uuid.UUID, senderId string) error
+
+	PostExists(ctx context.Context, postId uuid.UUID) (bool, error)
+	// Including soft deleted ones
+	GetPosts(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error)
+	GetPostCards(ctx context.Context, postIds []uuid.UUID) ([]PostCardQueryRes, error)
+}
+
+type Service interface {
+	CreateConversation(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateConversationRequest) (internal.Conversation, error)
+	GetConversation(ctx context.Context, reqUri ConversationUriRequest) (internal.Conversation, error)
+	GetConversations(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetConversationsQueryRequest) ([]ConversationResponse, error)
+	GetUnreadCount(ctx context.Context, reqUri internal.UserIdUriRequest) (UnreadCountResponse, error)
+
+	SendMessage(ctx context.Context, reqUri ConversationUriRequest, reqBody SendMessageRequest) (MessageResponse, error)
+	GetMessages(ctx context.Context, reqUri ConversationUriRequest, reqQuery GetMessagesQueryRequest) (GetMessagesResponse, error)
+	MarkAsRead(ctx context.Context, reqUri ConversationUriRequest, reqBody MarkAsReadRequest) error
+	// Lets participants subscribe to realtime.ConversationTopic
+	AuthorizeTopic(ctx context.Context, userId, topic string) error
+	DeleteMessage(ctx context.Context, reqUri MessageUriRequest) error
+
+	CreateGroup(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateGroupRequest) (internal.Conversation, error)
+	UpdateGroup(ctx context.Context, reqUri ConversationUriRequest, reqBody UpdateGroupRequest) (internal.Conversation, error)
+	AddParticipants(ctx context.Context, reqUri ConversationUriRequest, reqBody AddParticipantsRequest) (internal.Conversation, error)
+	RemoveParticipant(ctx context.Context, reqUri ParticipantUriRequest) error
+	LeaveGroup(ctx context.Context, reqUri ConversationUriRequest) error
+	UpdateRole(ctx context.Context, reqUri ParticipantUriRequest, reqBody UpdateRoleRequest) (internal.Conversation, error)
+}
@@ -0,0 +1,570 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+func (r gormRepository) UserExists(ctx context.Context, userId string) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&internal.User{}).Where("id = ?", userId).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r gormRepository) GetExistingUserIds(ctx context.Context, userIds []string) ([]string, error) {
+	var existingUserIds []string
+
+	if len(userIds) == 0 {
+		return existingUserIds, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.User{}).
+		Where("id IN ?", userIds).
+		Pluck("id", &existingUserIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return existingUserIds, nil
+}
+
+func (r gormRepository) GetOrCreateDirectConversation(ctx context.Context, directKey string, userIds []string) (internal.Conversation, error) {
+	var (
+		conversation = internal.Conversation{
+			DirectKey:     &directKey,
+			LastMessageAt: time.Now(),
+		}
+		tx = r.db.WithContext(ctx).Begin()
+	)
+
+	// Both users can start it at the same time
+	err := tx.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "direct_key"}},
+			DoNothing: true,
+		}).
+		Omit("Participants").
+		Create(&conversation).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, err
+	}
+
+	if err := tx.Where("direct_key = ?", directKey).First(&conversation).Error; err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, err
+	}
+
+	participants := make([]internal.ConversationParticipant, 0, len(userIds))
+	for _, userId := range userIds {
+		participants = append(participants, internal.ConversationParticipant{
+			ConversationID: conversation.ID,
+			UserID:         userId,
+		})
+	}
+
+	err = tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit("User").
+		Create(&participants).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, err
+	}
+
+	if err := tx.Preload("Participants.User").First(&conversation, "id = ?", conversation.ID).Error; err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (r gormRepository) GetConversations(ctx context.Context, userId string, page, limit int) ([]internal.Conversation, error) {
+	var conversations []internal.Conversation
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Participants.User").
+		Where("id IN (SELECT conversation_id FROM conversation_participants WHERE user_id = ?)", userId).
+		Order("last_message_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&conversations).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return conversations, nil
+}
+
+func (r gormRepository) GetLastMessages(ctx context.Context, conversationIds []uuid.UUID) ([]internal.Message, error) {
+	var messages []internal.Message
+
+	if len(conversationIds) == 0 {
+		return messages, nil
+	}
+
+	lastMessageIds := r.db.
+		Model(&internal.Message{}).
+		Select("DISTINCT ON (conversation_id) id").
+		Where("conversation_id IN ?", conversationIds).
+		Order("conversation_id, created_at DESC, id DESC")
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Sender").
+		Preload("TargetUser").
+		Where("id IN (?)", lastMessageIds).
+		Find(&messages).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) GetUnreadCounts(ctx context.Context, userId string, conversationIds []uuid.UUID) ([]UnreadCountQueryRes, error) {
+	var unreadCounts []UnreadCountQueryRes
+
+	tx := r.db.
+		WithContext(ctx).
+		Model(&internal.Message{}).
+		Select("messages.conversation_id, COUNT(*) AS unread_count").
+		Joins("JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id AND conversation_participants.user_id = ?", userId).
+		Where("messages.sender_id <> ?", userId).
+		Where("conversation_participants.last_read_at IS NULL OR messages.created_at > conversation_participants.last_read_at")
+
+	if len(conversationIds) > 0 {
+		tx = tx.Where("messages.conversation_id IN ?", conversationIds)
+	}
+
+	if err := tx.Group("messages.conversation_id").Scan(&unreadCounts).Error; err != nil {
+		return nil, err
+	}
+
+	return unreadCounts, nil
+}
+
+func (r gormRepository) GetParticipant(ctx context.Context, conversationId uuid.UUID, userId string) (internal.ConversationParticipant, error) {
+	var participant internal.ConversationParticipant
+
+	err := r.db.
+		WithContext(ctx).
+		Where("conversation_id = ? AND user_id = ?", conversationId, userId).
+		First(&participant).
+		Error
+	if err != nil {
+		return internal.ConversationParticipant{}, err
+	}
+
+	return participant, nil
+}
+
+func (r gormRepository) GetParticipantIds(ctx context.Context, conversationId uuid.UUID) ([]string, error) {
+	var userIds []string
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.ConversationParticipant{}).
+		Where("conversation_id = ?", conversationId).
+		Pluck("user_id", &userIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+// Never moves the marker backwards.
+func (r gormRepository) MarkAsRead(ctx context.Context, conversationId uuid.UUID, userId string, readAt time.Time) (bool, error) {
+	res := r.db.
+		WithContext(ctx).
+		Model(&internal.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationId, userId).
+		Where("last_read_at IS NULL OR last_read_at < ?", readAt).
+		Update("last_read_at", readAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r gormRepository) GetMessage(ctx context.Context, conversationId, messageId uuid.UUID) (internal.Message, error) {
+	var message internal.Message
+
+	err := r.db.
+		WithContext(ctx).
+		Where("id = ? AND conversation_id = ?", messageId, conversationId).
+		First(&message).
+		Error
+	if err != nil {
+		return internal.Message{}, err
+	}
+
+	return message, nil
+}
+
+func (r gormRepository) CreateMessage(ctx context.Context, message internal.Message) (internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	messages, err := createMessages(tx, []internal.Message{message})
+	if err != nil {
+		tx.Rollback()
+		return internal.Message{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Message{}, err
+	}
+
+	return messages[0], nil
+}
+
+// Also moves the conversation to the top and marks it as read for the senders.
+func createMessages(tx *gorm.DB, messages []internal.Message) ([]internal.Message, error) {
+	if err := tx.Omit("Sender", "TargetUser").Create(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	messageIds := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		messageIds = append(messageIds, message.ID)
+
+		err := tx.
+			Model(&internal.Conversation{}).
+			Where("id = ? AND last_message_at < ?", message.ConversationID, message.CreatedAt).
+			Update("last_message_at", message.CreatedAt).
+			Error
+		if err != nil {
+			return nil, err
+		}
+
+		// Sender has seen everything up to their own message
+		err = tx.
+			Model(&internal.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ?", message.ConversationID, message.SenderID).
+			Update("last_read_at", message.CreatedAt).
+			Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var created []internal.Message
+	err := tx.
+		Preload("Sender").
+		Preload("TargetUser").
+		Where("id IN ?", messageIds).
+		Order("created_at, id").
+		Find(&created).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r gormRepository) GetMessages(ctx context.Context, conversationId uuid.UUID, cursor *MessagesCursor, limit int) ([]internal.Message, error) {
+	var messages []internal.Message
+
+	tx := r.db.
+		WithContext(ctx).
+		Preload("Sender").
+		Preload("TargetUser").
+		Where("conversation_id = ?", conversationId)
+
+	if cursor != nil {
+		tx = tx.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	err := tx.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) DeleteMessage(ctx context.Context, conversationId, messageId uuid.UUID, senderId string) error {
+	res := r.db.
+		WithContext(ctx).
+		Where("id = ? AND conversation_id = ? AND sender_id = ?", messageId, conversationId, senderId).
+		Where("type IN ?", USER_TYPES).
+		Delete(&internal.Message{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) CreateGroup(ctx context.Context, conversation internal.Conversation, participants []internal.ConversationParticipant, systemMessage internal.Message) (internal.Conversation, []internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := tx.Omit("Participants").Create(&conversation).Error; err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, nil, err
+	}
+
+	for i := range participants {
+		participants[i].ConversationID = conversation.ID
+	}
+
+	if err := tx.Omit("User").Create(&participants).Error; err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, nil, err
+	}
+
+	systemMessage.ConversationID = conversation.ID
+	messages, err := createMessages(tx, []internal.Message{systemMessage})
+	if err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, nil, err
+	}
+
+	if err := tx.Preload("Participants.User").First(&conversation, "id = ?", conversation.ID).Error; err != nil {
+		tx.Rollback()
+		return internal.Conversation{}, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Conversation{}, nil, err
+	}
+
+	return conversation, messages, nil
+}
+
+func (r gormRepository) GetConversation(ctx context.Context, conversationId uuid.UUID) (internal.Conversation, error) {
+	var conversation internal.Conversation
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Participants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Participants.User").
+		First(&conversation, "id = ?", conversationId).
+		Error
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (r gormRepository) UpdateGroup(ctx context.Context, conversationId uuid.UUID, updates map[string]interface{}, systemMessage internal.Message) ([]internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := tx.Model(&internal.Conversation{}).Where("id = ?", conversationId).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	messages, err := createMessages(tx, []internal.Message{systemMessage})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) AddParticipants(ctx context.Context, participants []internal.ConversationParticipant, systemMessages []internal.Message) ([]internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := tx.Omit("User").Create(&participants).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	messages, err := createMessages(tx, systemMessages)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) RemoveParticipant(ctx context.Context, conversationId uuid.UUID, userId string, systemMessage internal.Message) ([]internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// Message first, a leaving sender is still a participant here
+	messages, err := createMessages(tx, []internal.Message{systemMessage})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	res := tx.Where("conversation_id = ? AND user_id = ?", conversationId, userId).Delete(&internal.ConversationParticipant{})
+	if res.Error != nil {
+		tx.Rollback()
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, ErrParticipantNotFound
+	}
+
+	err = tx.Exec(`
+	UPDATE conversation_participants SET role = @admin
+	WHERE conversation_id = @conversationId
+	AND user_id = (
+		SELECT user_id FROM conversation_participants
+		WHERE conversation_id = @conversationId
+		ORDER BY created_at, user_id
+		LIMIT 1
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM conversation_participants
+		WHERE conversation_id = @conversationId AND role = @admin
+	)
+	`, map[string]interface{}{
+		"admin":          ROLE_ADMIN,
+		"conversationId": conversationId,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) UpdateRole(ctx context.Context, conversationId uuid.UUID, userId, role string, systemMessage internal.Message) ([]internal.Message, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	res := tx.
+		Model(&internal.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationId, userId).
+		Update("role", role)
+	if res.Error != nil {
+		tx.Rollback()
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, ErrParticipantNotFound
+	}
+
+	messages, err := createMessages(tx, []internal.Message{systemMessage})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r gormRepository) PostExists(ctx context.Context, postId uuid.UUID) (bool, error) {
+	var count int64
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND status = ?", postId, internal.POST_STATUS_PUBLISHED).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r gormRepository) GetPosts(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error) {
+	var posts []internal.Post
+
+	if len(postIds) == 0 {
+		return posts, nil
+	}
+
+	if err := r.db.WithContext(ctx).Unscoped().Preload("CreatedBy").Where("id IN ?", postIds).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r gormRepository) GetPostCards(ctx context.Context, postIds []uuid.UUID) ([]PostCardQueryRes, error) {
+	var cards []PostCardQueryRes
+
+	if len(postIds) == 0 {
+		return cards, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Table("posts").
+		Select(`posts.id,
+		(SELECT COUNT(*) FROM user_liked_posts WHERE user_liked_posts.post_id = posts.id) AS total_likes,
+		(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) AS total_comments`).
+		Where("posts.id IN ?", postIds).
+		Scan(&cards).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
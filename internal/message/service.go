@@ -0,0 +1,709 @@
+package message
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"gorm.io/gorm"
+)
+
+type messageService struct {
+	repo      Repository
+	publisher realtime.Publisher
+}
+
+func NewService(repo Repository, publisher realtime.Publisher) Service {
+	return messageService{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+// Same for both users regardless of who starts the conversation.
+func directKey(userId, otherUserId string) string {
+	if userId > otherUserId {
+		userId, otherUserId = otherUserId, userId
+	}
+
+	return userId + ":" + otherUserId
+}
+
+func (s messageService) CreateConversation(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateConversationRequest) (internal.Conversation, error) {
+	if reqUri.UserId == reqBody.UserId {
+		return internal.Conversation{}, ErrSelfConversation
+	}
+
+	exists, err := s.repo.UserExists(ctx, reqBody.UserId)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	if !exists {
+		return internal.Conversation{}, gorm.ErrRecordNotFound
+	}
+
+	conversation, err := s.repo.GetOrCreateDirectConversation(ctx, directKey(reqUri.UserId, reqBody.UserId), []string{reqUri.UserId, reqBody.UserId})
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (s messageService) GetConversations(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetConversationsQueryRequest) ([]ConversationResponse, error) {
+	conversations, err := s.repo.GetConversations(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationIds := make([]uuid.UUID, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIds = append(conversationIds, conversation.ID)
+	}
+
+	responses := []ConversationResponse{}
+	if len(conversationIds) == 0 {
+		return responses, nil
+	}
+
+	lastMessages, err := s.repo.GetLastMessages(ctx, conversationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	lastMessageResponses, err := s.toResponses(ctx, lastMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	lastMessageByConversation := make(map[uuid.UUID]MessageResponse, len(lastMessageResponses))
+	for _, lastMessage := range lastMessageResponses {
+		lastMessageByConversation[lastMessage.ConversationID] = lastMessage
+	}
+
+	unreadCounts, err := s.repo.GetUnreadCounts(ctx, reqUri.UserId, conversationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCountByConversation := make(map[uuid.UUID]int, len(unreadCounts))
+	for _, unreadCount := range unreadCounts {
+		unreadCountByConversation[unreadCount.ConversationID] = unreadCount.UnreadCount
+	}
+
+	for _, conversation := range conversations {
+		res := ConversationResponse{
+			ID:            conversation.ID,
+			IsGroup:       conversation.IsGroup,
+			Name:          conversation.Name,
+			PictureLink:   conversation.PictureLink,
+			Participants:  []internal.User{},
+			LastMessageAt: conversation.LastMessageAt,
+			UnreadCount:   unreadCountByConversation[conversation.ID],
+		}
+
+		for _, participant := range conversation.Participants {
+			if participant.UserID != reqUri.UserId {
+				res.Participants = append(res.Participants, participant.User)
+			}
+		}
+
+		if lastMessage, ok := lastMessageByConversation[conversation.ID]; ok {
+			res.LastMessage = &lastMessage
+		}
+
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+func (s messageService) GetUnreadCount(ctx context.Context, reqUri internal.UserIdUriRequest) (UnreadCountResponse, error) {
+	unreadCounts, err := s.repo.GetUnreadCounts(ctx, reqUri.UserId, nil)
+	if err != nil {
+		return UnreadCountResponse{}, err
+	}
+
+	res := UnreadCountResponse{
+		UnreadConversations: len(unreadCounts),
+	}
+	for _, unreadCount := range unreadCounts {
+		res.UnreadMessages += unreadCount.UnreadCount
+	}
+
+	return res, nil
+}
+
+// Not being a participant looks the same as the conversation not existing.
+func (s messageService) checkParticipant(ctx context.Context, conversationId uuid.UUID, userId string) error {
+	_, err := s.repo.GetParticipant(ctx, conversationId, userId)
+	return err
+}
+
+func (s messageService) SendMessage(ctx context.Context, reqUri ConversationUriRequest, reqBody SendMessageRequest) (MessageResponse, error) {
+	if strings.TrimSpace(reqBody.Body) == "" && reqBody.PostId == "" {
+		return MessageResponse{}, ErrEmptyMessage
+	}
+
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if err := s.checkParticipant(ctx, conversationId, reqUri.UserId); err != nil {
+		return MessageResponse{}, err
+	}
+
+	message := internal.Message{
+		ConversationID: conversationId,
+		SenderID:       reqUri.UserId,
+		Type:           TYPE_TEXT,
+		Body:           reqBody.Body,
+	}
+
+	if reqBody.PostId != "" {
+		postId, _ := uuid.Parse(reqBody.PostId)
+
+		exists, err := s.repo.PostExists(ctx, postId)
+		if err != nil {
+			return MessageResponse{}, err
+		}
+
+		if !exists {
+			return MessageResponse{}, ErrPostNotFound
+		}
+
+		message.Type = TYPE_POST_SHARE
+		message.PostID = &postId
+	}
+
+	message, err := s.repo.CreateMessage(ctx, message)
+	if err != nil {
+		return MessageResponse{}, err
+	}
+
+	responses, err := s.toResponses(ctx, []internal.Message{message})
+	if err != nil {
+		return MessageResponse{}, err
+	}
+
+	s.publish(ctx, conversationId, realtime.TYPE_MESSAGE, responses[0])
+	return responses[0], nil
+}
+
+// Embeds shared posts, deleted ones look like they do in GetPostById. Posts that aren't
+// published anymore (e.g. archived since) look deleted as well.
+func (s messageService) toResponses(ctx context.Context, messages []internal.Message) ([]MessageResponse, error) {
+	var postIds []uuid.UUID
+	for _, message := range messages {
+		if message.PostID != nil {
+			postIds = append(postIds, *message.PostID)
+		}
+	}
+
+	posts, err := s.repo.GetPosts(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	postsById := make(map[uuid.UUID]internal.Post, len(posts))
+	for _, post := range posts {
+		postsById[post.ID] = post
+	}
+
+	cards, err := s.repo.GetPostCards(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	cardsById := make(map[uuid.UUID]PostCardQueryRes, len(cards))
+	for _, card := range cards {
+		cardsById[card.ID] = card
+	}
+
+	responses := make([]MessageResponse, 0, len(messages))
+	for _, message := range messages {
+		res := MessageResponse{
+			Message: message,
+		}
+
+		if message.PostID != nil {
+			post, ok := postsById[*message.PostID]
+			switch {
+			case !ok:
+				// Hard deleted, nothing left but the id
+				res.Post = internal.DeletedCommentOrPostResponse{
+					ID:        *message.PostID,
+					IsDeleted: true,
+				}
+			case post.DeletedAt.Valid || post.Status != internal.POST_STATUS_PUBLISHED:
+				res.Post = internal.DeletedCommentOrPostResponse{
+					ID:        post.ID,
+					CreatedAt: post.CreatedAt,
+					UpdatedAt: post.UpdatedAt,
+					DeletedAt: post.DeletedAt,
+					IsDeleted: true,
+					CreatedBy: post.CreatedBy,
+				}
+			default:
+				card := cardsById[post.ID]
+				res.Post = PostCardResponse{
+					ID:            post.ID,
+					CreatedAt:     post.CreatedAt,
+					CreatedBy:     post.CreatedBy,
+					PictureLink:   post.PictureLink,
+					Title:         post.Title,
+					Description:   post.Description,
+					TotalLikes:    card.TotalLikes,
+					TotalComments: card.TotalComments,
+				}
+			}
+		} else if message.Type == TYPE_POST_SHARE {
+			// Purged from the trash, not even the id is left
+			res.Post = internal.DeletedCommentOrPostResponse{
+				IsDeleted: true,
+			}
+		}
+
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+func (s messageService) GetMessages(ctx context.Context, reqUri ConversationUriRequest, reqQuery GetMessagesQueryRequest) (GetMessagesResponse, error) {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if err := s.checkParticipant(ctx, conversationId, reqUri.UserId); err != nil {
+		return GetMessagesResponse{}, err
+	}
+
+	var cursor *MessagesCursor
+	if reqQuery.Cursor != "" {
+		decoded, err := decodeMessagesCursor(reqQuery.Cursor)
+		if err != nil {
+			return GetMessagesResponse{}, err
+		}
+		cursor = &decoded
+	}
+
+	messages, err := s.repo.GetMessages(ctx, conversationId, cursor, reqQuery.Limit)
+	if err != nil {
+		return GetMessagesResponse{}, err
+	}
+
+	// Opening the conversation shows the latest messages
+	if cursor == nil && len(messages) > 0 {
+		if err := s.markAsRead(ctx, conversationId, reqUri.UserId, messages[0].CreatedAt); err != nil {
+			return GetMessagesResponse{}, err
+		}
+	}
+
+	// Less than a full page means there is nothing left to fetch
+	var nextCursor string
+	if len(messages) == reqQuery.Limit {
+		last := messages[len(messages)-1]
+		nextCursor = encodeMessagesCursor(MessagesCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	responses, err := s.toResponses(ctx, messages)
+	if err != nil {
+		return GetMessagesResponse{}, err
+	}
+
+	return GetMessagesResponse{
+		NextCursor: nextCursor,
+		Messages:   responses,
+	}, nil
+}
+
+func (s messageService) MarkAsRead(ctx context.Context, reqUri ConversationUriRequest, reqBody MarkAsReadRequest) error {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if err := s.checkParticipant(ctx, conversationId, reqUri.UserId); err != nil {
+		return err
+	}
+
+	readAt := time.Now()
+	if reqBody.MessageId != "" {
+		messageId, _ := uuid.Parse(reqBody.MessageId)
+
+		message, err := s.repo.GetMessage(ctx, conversationId, messageId)
+		if err != nil {
+			return err
+		}
+		readAt = message.CreatedAt
+	}
+
+	return s.markAsRead(ctx, conversationId, reqUri.UserId, readAt)
+}
+
+// Other participants get a read receipt when the marker moves.
+func (s messageService) markAsRead(ctx context.Context, conversationId uuid.UUID, userId string, readAt time.Time) error {
+	moved, err := s.repo.MarkAsRead(ctx, conversationId, userId, readAt)
+	if err != nil || !moved {
+		return err
+	}
+
+	s.publish(ctx, conversationId, realtime.TYPE_READ_RECEIPT, realtime.ReadReceiptEventData{
+		ConversationID: conversationId.String(),
+		UserID:         userId,
+		LastReadAt:     readAt,
+	})
+	return nil
+}
+
+func (s messageService) AuthorizeTopic(ctx context.Context, userId, topic string) error {
+	conversationId, err := uuid.Parse(strings.TrimPrefix(topic, realtime.CONVERSATION_TOPIC_PREFIX))
+	if err != nil {
+		return fmt.Errorf("topic %v not allowed", topic)
+	}
+
+	if err := s.checkParticipant(ctx, conversationId, userId); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("topic %v not allowed", topic)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s messageService) DeleteMessage(ctx context.Context, reqUri MessageUriRequest) error {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	messageId, _ := uuid.Parse(reqUri.MessageId)
+
+	if err := s.repo.DeleteMessage(ctx, conversationId, messageId, reqUri.UserId); err != nil {
+		return err
+	}
+
+	s.publish(ctx, conversationId, realtime.TYPE_MESSAGE_GONE, realtime.MessageDeletedEventData{
+		ConversationID: reqUri.ConversationId,
+		MessageID:      reqUri.MessageId,
+	})
+	return nil
+}
+
+// Sends to every participant's user topic and to extraUserIds (e.g. removed members), best effort.
+func (s messageService) publish(ctx context.Context, conversationId uuid.UUID, eventType string, data any, extraUserIds ...string) {
+	participantIds, err := s.repo.GetParticipantIds(ctx, conversationId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get participants of conversation %v: %v", conversationId, err.Error())
+		return
+	}
+
+	for _, participantId := range append(participantIds, extraUserIds...) {
+		err := s.publisher.Publish(ctx, realtime.Event{
+			Topic: realtime.UserTopic(participantId),
+			Type:  eventType,
+			Data:  data,
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to publish %v of conversation %v: %v", eventType, conversationId, err.Error())
+		}
+	}
+}
+
+func (s messageService) publishMessages(ctx context.Context, conversationId uuid.UUID, messages []internal.Message, extraUserIds ...string) {
+	// System messages never share posts, no need for toResponses
+	for _, message := range messages {
+		s.publish(ctx, conversationId, realtime.TYPE_MESSAGE, message, extraUserIds...)
+	}
+}
+
+func (s messageService) GetConversation(ctx context.Context, reqUri ConversationUriRequest) (internal.Conversation, error) {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if err := s.checkParticipant(ctx, conversationId, reqUri.UserId); err != nil {
+		return internal.Conversation{}, err
+	}
+
+	return s.repo.GetConversation(ctx, conversationId)
+}
+
+// Group where userId participates, ErrNotAdmin if adminOnly and they aren't one.
+func (s messageService) getGroup(ctx context.Context, conversationId uuid.UUID, userId string, adminOnly bool) (internal.Conversation, error) {
+	conversation, err := s.repo.GetConversation(ctx, conversationId)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	var participant *internal.ConversationParticipant
+	for i := range conversation.Participants {
+		if conversation.Participants[i].UserID == userId {
+			participant = &conversation.Participants[i]
+			break
+		}
+	}
+
+	if participant == nil {
+		return internal.Conversation{}, gorm.ErrRecordNotFound
+	}
+
+	if !conversation.IsGroup {
+		return internal.Conversation{}, ErrNotGroup
+	}
+
+	if adminOnly && participant.Role != ROLE_ADMIN {
+		return internal.Conversation{}, ErrNotAdmin
+	}
+
+	return conversation, nil
+}
+
+// Removes duplicates and excluded ids, errors if any of the rest doesn't exist.
+func (s messageService) checkUsers(ctx context.Context, userIds []string, excluded map[string]bool) ([]string, error) {
+	var uniqueUserIds []string
+	for _, userId := range userIds {
+		if excluded[userId] {
+			continue
+		}
+		excluded[userId] = true
+		uniqueUserIds = append(uniqueUserIds, userId)
+	}
+
+	existingUserIds, err := s.repo.GetExistingUserIds(ctx, uniqueUserIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(existingUserIds) != len(uniqueUserIds) {
+		return nil, ErrUsersNotFound
+	}
+
+	return uniqueUserIds, nil
+}
+
+func (s messageService) CreateGroup(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateGroupRequest) (internal.Conversation, error) {
+	userIds, err := s.checkUsers(ctx, reqBody.UserIds, map[string]bool{reqUri.UserId: true})
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	if len(userIds)+1 > MAXIMUM_GROUP_PARTICIPANTS {
+		return internal.Conversation{}, ErrTooManyParticipants
+	}
+
+	participants := []internal.ConversationParticipant{{
+		UserID: reqUri.UserId,
+		Role:   ROLE_ADMIN,
+	}}
+	for _, userId := range userIds {
+		participants = append(participants, internal.ConversationParticipant{
+			UserID: userId,
+			Role:   ROLE_MEMBER,
+		})
+	}
+
+	conversation, messages, err := s.repo.CreateGroup(ctx, internal.Conversation{
+		IsGroup:       true,
+		Name:          reqBody.Name,
+		PictureLink:   reqBody.PictureLink,
+		LastMessageAt: time.Now(),
+	}, participants, internal.Message{
+		SenderID: reqUri.UserId,
+		Type:     TYPE_GROUP_CREATED,
+		Body:     reqBody.Name,
+	})
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	s.publishMessages(ctx, conversation.ID, messages)
+	return conversation, nil
+}
+
+func (s messageService) UpdateGroup(ctx context.Context, reqUri ConversationUriRequest, reqBody UpdateGroupRequest) (internal.Conversation, error) {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	conversation, err := s.getGroup(ctx, conversationId, reqUri.UserId, true)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	updates := make(map[string]interface{})
+	if reqBody.Name != nil {
+		updates["name"] = *reqBody.Name
+	}
+	if reqBody.PictureLink != nil {
+		updates["picture_link"] = *reqBody.PictureLink
+	}
+
+	if len(updates) == 0 {
+		return conversation, nil
+	}
+
+	messages, err := s.repo.UpdateGroup(ctx, conversationId, updates, internal.Message{
+		ConversationID: conversationId,
+		SenderID:       reqUri.UserId,
+		Type:           TYPE_GROUP_UPDATED,
+	})
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	s.publishMessages(ctx, conversationId, messages)
+	return s.repo.GetConversation(ctx, conversationId)
+}
+
+func (s messageService) AddParticipants(ctx context.Context, reqUri ConversationUriRequest, reqBody AddParticipantsRequest) (internal.Conversation, error) {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	conversation, err := s.getGroup(ctx, conversationId, reqUri.UserId, true)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	// Adding someone who is already in is a no-op
+	excluded := make(map[string]bool, len(conversation.Participants))
+	for _, participant := range conversation.Participants {
+		excluded[participant.UserID] = true
+	}
+
+	userIds, err := s.checkUsers(ctx, reqBody.UserIds, excluded)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	if len(userIds) == 0 {
+		return conversation, nil
+	}
+
+	if len(conversation.Participants)+len(userIds) > MAXIMUM_GROUP_PARTICIPANTS {
+		return internal.Conversation{}, ErrTooManyParticipants
+	}
+
+	var (
+		participants   []internal.ConversationParticipant
+		systemMessages []internal.Message
+	)
+	for _, userId := range userIds {
+		targetUserId := userId
+		participants = append(participants, internal.ConversationParticipant{
+			ConversationID: conversationId,
+			UserID:         userId,
+			Role:           ROLE_MEMBER,
+		})
+		systemMessages = append(systemMessages, internal.Message{
+			ConversationID: conversationId,
+			SenderID:       reqUri.UserId,
+			Type:           TYPE_MEMBER_ADDED,
+			TargetUserID:   &targetUserId,
+		})
+	}
+
+	messages, err := s.repo.AddParticipants(ctx, participants, systemMessages)
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	s.publishMessages(ctx, conversationId, messages)
+	return s.repo.GetConversation(ctx, conversationId)
+}
+
+func (s messageService) RemoveParticipant(ctx context.Context, reqUri ParticipantUriRequest) error {
+	if reqUri.OtherUserId == reqUri.UserId {
+		return ErrRemoveSelf
+	}
+
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if _, err := s.getGroup(ctx, conversationId, reqUri.UserId, true); err != nil {
+		return err
+	}
+
+	messages, err := s.repo.RemoveParticipant(ctx, conversationId, reqUri.OtherUserId, internal.Message{
+		ConversationID: conversationId,
+		SenderID:       reqUri.UserId,
+		Type:           TYPE_MEMBER_REMOVED,
+		TargetUserID:   &reqUri.OtherUserId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Removed member still learns about it
+	s.publishMessages(ctx, conversationId, messages, reqUri.OtherUserId)
+	return nil
+}
+
+func (s messageService) LeaveGroup(ctx context.Context, reqUri ConversationUriRequest) error {
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if _, err := s.getGroup(ctx, conversationId, reqUri.UserId, false); err != nil {
+		return err
+	}
+
+	messages, err := s.repo.RemoveParticipant(ctx, conversationId, reqUri.UserId, internal.Message{
+		ConversationID: conversationId,
+		SenderID:       reqUri.UserId,
+		Type:           TYPE_MEMBER_LEFT,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishMessages(ctx, conversationId, messages, reqUri.UserId)
+	return nil
+}
+
+func (s messageService) UpdateRole(ctx context.Context, reqUri ParticipantUriRequest, reqBody UpdateRoleRequest) (internal.Conversation, error) {
+	// Keeps at least one admin in the group
+	if reqUri.OtherUserId == reqUri.UserId {
+		return internal.Conversation{}, ErrChangeOwnRole
+	}
+
+	conversationId, _ := uuid.Parse(reqUri.ConversationId)
+	if _, err := s.getGroup(ctx, conversationId, reqUri.UserId, true); err != nil {
+		return internal.Conversation{}, err
+	}
+
+	messages, err := s.repo.UpdateRole(ctx, conversationId, reqUri.OtherUserId, reqBody.Role, internal.Message{
+		ConversationID: conversationId,
+		SenderID:       reqUri.UserId,
+		Type:           TYPE_ROLE_UPDATED,
+		Body:           reqBody.Role,
+		TargetUserID:   &reqUri.OtherUserId,
+	})
+	if err != nil {
+		return internal.Conversation{}, err
+	}
+
+	s.publishMessages(ctx, conversationId, messages)
+	return s.repo.GetConversation(ctx, conversationId)
+}
+
+// Cursor is "created_at|id" encoded in base64 so clients treat it as opaque.
+func encodeMessagesCursor(cursor MessagesCursor) string {
+	raw := fmt.Sprintf("%s|%s",
+		cursor.CreatedAt.Format(time.RFC3339Nano),
+		cursor.ID.String(),
+	)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMessagesCursor(encoded string) (MessagesCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return MessagesCursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 2 {
+		return MessagesCursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return MessagesCursor{}, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return MessagesCursor{}, ErrInvalidCursor
+	}
+
+	return MessagesCursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	}, nil
+}
@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/push"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(db *gorm.DB, publisher realtime.Publisher, dispatcher push.Dispatcher, preferences PreferenceChecker) Handler {
+	return Handler{
+		Service: NewService(NewRepository(db), publisher, dispatcher, preferences),
+	}
+}
+
+const (
+	MAXIMUM_LIMIT = 50
+	MINIMUM_LIMIT = 10
+	MINIMUM_PAGE  = 1
+)
+
+func (h Handler) GetNotifications(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetNotificationsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's notifications.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's notifications.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	notifications, err := h.Service.GetNotifications(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's notifications.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's notifications fetched successfully.",
+		Data:    notifications,
+	})
+}
+
+func (h Handler) GetUnreadCount(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's unread notifications count.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's unread notifications count.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	count, err := h.Service.GetUnreadCount(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's unread notifications count.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's unread notifications count fetched successfully.",
+		Data:    count,
+	})
+}
+
+func (h Handler) MarkAllAsRead(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to mark notifications as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to mark notifications as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	err := h.Service.MarkAllAsRead(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to mark notifications as read.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Notifications marked as read successfully.",
+	})
+}
+
+func (h Handler) MarkAsRead(ctx *gin.Context) {
+	var reqUri NotificationUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to mark notification as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to mark notification as read.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	err := h.Service.MarkAsRead(ctx.Request.Context(), reqUri)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to mark notification as read, notification not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to mark notification as read.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: fmt.Sprintf("Notification with id %v marked as read successfully.", reqUri.NotificationId),
+	})
+}
@@ -0,0 +1,100 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+const (
+	TYPE_POST_LIKE    = "post_like"
+	TYPE_COMMENT_LIKE = "comment_like"
+	TYPE_COMMENT      = "comment"
+	TYPE_REPLY        = "reply"
+	TYPE_FOLLOW       = "follow"
+)
+
+// Something that happened, recipient is resolved from PostID / CommentID when empty:
+// - TYPE_POST_LIKE, TYPE_COMMENT -> owner of PostID
+// - TYPE_COMMENT_LIKE, TYPE_REPLY -> owner of CommentID
+type Event struct {
+	Type        string
+	ActorID     string
+	RecipientID string
+	PostID      *uuid.UUID
+	CommentID   *uuid.UUID
+}
+
+// Implemented by Service, used by other packages to emit notifications.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+	// Removes unread notifications of an undone action (unlike, unfollow, etc.)
+	Retract(ctx context.Context, event Event) error
+}
+
+// Decides whether a user wants a notification type in their inbox.
+type PreferenceChecker interface {
+	AllowsInApp(ctx context.Context, userId, notificationType string) (bool, error)
+}
+
+type NotificationUriRequest struct {
+	UserId         string `uri:"id" binding:"required"`
+	NotificationId string `uri:"notificationId" binding:"required,uuid"`
+}
+
+type GetNotificationsQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// Notifications with the same type, post, comment and read state.
+type NotificationGroupQueryRes struct {
+	ID          uuid.UUID
+	Type        string
+	PostID      *uuid.UUID
+	CommentID   *uuid.UUID
+	IsRead      bool
+	LatestAt    time.Time
+	TotalActors int
+	ActorIDs    string // Comma separated, latest first
+}
+
+type NotificationGroupResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	Message     string          `json:"message"`
+	PostID      *uuid.UUID      `json:"post_id"`
+	CommentID   *uuid.UUID      `json:"comment_id"`
+	IsRead      bool            `json:"is_read"`
+	LatestAt    time.Time       `json:"latest_at"`
+	TotalActors int             `json:"total_actors"`
+	Actors      []internal.User `json:"actors"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+type Repository interface {
+	CreateNotification(ctx context.Context, notification internal.Notification) (internal.Notification, error)
+	DeleteUnreadNotifications(ctx context.Context, notification internal.Notification) error
+	GetRecipientId(ctx context.Context, event Event) (string, error)
+
+	GetNotificationGroups(ctx context.Context, userId string, page, limit int) ([]NotificationGroupQueryRes, error)
+	GetUsers(ctx context.Context, userIds []string) ([]internal.User, error)
+	GetUser(ctx context.Context, userId string) (internal.User, error)
+	GetUnreadCount(ctx context.Context, userId string) (int, error)
+	MarkAllAsRead(ctx context.Context, userId string) error
+	MarkGroupAsRead(ctx context.Context, userId string, notificationId uuid.UUID) error
+}
+
+type Service interface {
+	Notifier
+
+	GetNotifications(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetNotificationsQueryRequest) ([]NotificationGroupResponse, error)
+	GetUnreadCount(ctx context.Context, reqUri internal.UserIdUriRequest) (UnreadCountResponse, error)
+	MarkAllAsRead(ctx context.Context, reqUri internal.UserIdUriRequest) error
+	MarkAsRead(ctx context.Context, reqUri NotificationUriRequest) error
+}
@@ -0,0 +1,175 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+func (r gormRepository) CreateNotification(ctx context.Context, notification internal.Notification) (internal.Notification, error) {
+	if err := r.db.WithContext(ctx).Create(&notification).Error; err != nil {
+		return internal.Notification{}, err
+	}
+
+	if err := r.db.WithContext(ctx).Preload("Actor").First(&notification, "id = ?", notification.ID).Error; err != nil {
+		return internal.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+func (r gormRepository) DeleteUnreadNotifications(ctx context.Context, notification internal.Notification) error {
+	tx := r.db.
+		WithContext(ctx).
+		Where("recipient_id = ? AND actor_id = ? AND type = ? AND read_at IS NULL", notification.RecipientID, notification.ActorID, notification.Type)
+
+	if notification.PostID != nil {
+		tx = tx.Where("post_id = ?", notification.PostID)
+	}
+
+	if notification.CommentID != nil {
+		tx = tx.Where("comment_id = ?", notification.CommentID)
+	}
+
+	return tx.Delete(&internal.Notification{}).Error
+}
+
+func (r gormRepository) GetRecipientId(ctx context.Context, event Event) (string, error) {
+	var (
+		recipientId string
+		tx          = r.db.WithContext(ctx).Select("user_id")
+	)
+
+	switch event.Type {
+	case TYPE_POST_LIKE, TYPE_COMMENT:
+		tx = tx.Model(&internal.Post{}).Where("id = ?", event.PostID)
+	case TYPE_COMMENT_LIKE, TYPE_REPLY:
+		tx = tx.Model(&internal.Comment{}).Where("id = ?", event.CommentID)
+	default:
+		return "", gorm.ErrRecordNotFound
+	}
+
+	res := tx.Limit(1).Scan(&recipientId)
+	if res.Error != nil {
+		return "", res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	return recipientId, nil
+}
+
+const notificationGroupsQuery = `
+SELECT
+	(array_agg(id ORDER BY created_at DESC))[1] AS id,
+	type,
+	post_id,
+	comment_id,
+	read_at IS NOT NULL AS is_read,
+	MAX(created_at) AS latest_at,
+	COUNT(DISTINCT actor_id) AS total_actors,
+	string_agg(actor_id, ',' ORDER BY created_at DESC) AS actor_ids
+FROM notifications
+WHERE recipient_id = ?
+GROUP BY type, post_id, comment_id, read_at IS NOT NULL
+ORDER BY latest_at DESC
+LIMIT ? OFFSET ?`
+
+func (r gormRepository) GetNotificationGroups(ctx context.Context, userId string, page, limit int) ([]NotificationGroupQueryRes, error) {
+	var groups []NotificationGroupQueryRes
+
+	offset := (page - 1) * limit
+	err := r.db.WithContext(ctx).Raw(notificationGroupsQuery, userId, limit, offset).Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (r gormRepository) GetUsers(ctx context.Context, userIds []string) ([]internal.User, error) {
+	var users []internal.User
+
+	if len(userIds) == 0 {
+		return users, nil
+	}
+
+	if err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r gormRepository) GetUnreadCount(ctx context.Context, userId string) (int, error) {
+	var count int64
+
+	unreadGroups := r.db.
+		Model(&internal.Notification{}).
+		Select("1").
+		Where("recipient_id = ? AND read_at IS NULL", userId).
+		Group("type, post_id, comment_id")
+
+	err := r.db.WithContext(ctx).Table("(?) AS unread_groups", unreadGroups).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (r gormRepository) MarkAllAsRead(ctx context.Context, userId string) error {
+	return r.db.
+		WithContext(ctx).
+		Model(&internal.Notification{}).
+		Where("recipient_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now()).
+		Error
+}
+
+// Marks every unread notification grouped together with notificationId as read.
+func (r gormRepository) MarkGroupAsRead(ctx context.Context, userId string, notificationId uuid.UUID) error {
+	var notification internal.Notification
+
+	err := r.db.
+		WithContext(ctx).
+		Where("id = ? AND recipient_id = ?", notificationId, userId).
+		First(&notification).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.
+		WithContext(ctx).
+		Model(&internal.Notification{}).
+		Where("recipient_id = ? AND type = ? AND read_at IS NULL", userId, notification.Type).
+		Where("post_id IS NOT DISTINCT FROM ? AND comment_id IS NOT DISTINCT FROM ?", notification.PostID, notification.CommentID).
+		Update("read_at", time.Now()).
+		Error
+}
+
+func (r gormRepository) GetUser(ctx context.Context, userId string) (internal.User, error) {
+	var user internal.User
+
+	if err := r.db.WithContext(ctx).Where("id = ?", userId).First(&user).Error; err != nil {
+		return internal.User{}, err
+	}
+
+	return user, nil
+}
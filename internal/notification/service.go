@@ -0,0 +1,261 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/push"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"gorm.io/gorm"
+)
+
+// How many actors are returned (and named in the message) per group.
+const MAXIMUM_ACTORS_PER_GROUP = 3
+
+type notificationService struct {
+	repo        Repository
+	publisher   realtime.Publisher
+	dispatcher  push.Dispatcher
+	preferences PreferenceChecker
+}
+
+func NewService(repo Repository, publisher realtime.Publisher, dispatcher push.Dispatcher, preferences PreferenceChecker) Service {
+	return notificationService{
+		repo:        repo,
+		publisher:   publisher,
+		dispatcher:  dispatcher,
+		preferences: preferences,
+	}
+}
+
+func (s notificationService) toNotification(ctx context.Context, event Event) (internal.Notification, error) {
+	recipientId := event.RecipientID
+	if recipientId == "" {
+		var err error
+		recipientId, err = s.repo.GetRecipientId(ctx, event)
+		if err != nil {
+			return internal.Notification{}, err
+		}
+	}
+
+	return internal.Notification{
+		RecipientID: recipientId,
+		ActorID:     event.ActorID,
+		Type:        event.Type,
+		PostID:      event.PostID,
+		CommentID:   event.CommentID,
+	}, nil
+}
+
+func (s notificationService) Notify(ctx context.Context, event Event) error {
+	notification, err := s.toNotification(ctx, event)
+	if err != nil {
+		// Post or comment is already gone, nobody to notify
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Don't notify users about their own actions
+	if notification.RecipientID == notification.ActorID {
+		return nil
+	}
+
+	allowsInApp, err := s.preferences.AllowsInApp(ctx, notification.RecipientID, notification.Type)
+	if err != nil {
+		return err
+	}
+
+	if allowsInApp {
+		notification, err = s.repo.CreateNotification(ctx, notification)
+		if err != nil {
+			return err
+		}
+
+		// Connected clients are a bonus, the notification is already stored
+		err = s.publisher.Publish(ctx, realtime.Event{
+			Topic: realtime.UserTopic(notification.RecipientID),
+			Type:  realtime.TYPE_NOTIFICATION,
+			Data:  notification,
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to publish notification %v: %v", notification.ID, err.Error())
+		}
+	} else {
+		// Push still needs the actor for its message
+		notification.Actor, err = s.repo.GetUser(ctx, notification.ActorID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+	}
+
+	// Providers can be slow, don't hold up the request that caused the notification
+	go s.dispatchPush(context.WithoutCancel(ctx), notification)
+
+	return nil
+}
+
+func (s notificationService) dispatchPush(ctx context.Context, notification internal.Notification) {
+	err := s.dispatcher.Dispatch(ctx, notification.RecipientID, notification.Type, push.Message{
+		Title: "its-gram",
+		Body:  groupMessage(notification.Type, []internal.User{notification.Actor}, 1),
+		Data:  pushData(notification),
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to push notification %v: %v", notification.ID, err.Error())
+	}
+}
+
+// Lets the app open the related post or comment when the push is tapped.
+func pushData(notification internal.Notification) map[string]string {
+	data := map[string]string{
+		"type":     notification.Type,
+		"actor_id": notification.ActorID,
+	}
+
+	// Not stored when the recipient turned off in-app notifications
+	if notification.ID != uuid.Nil {
+		data["notification_id"] = notification.ID.String()
+	}
+
+	if notification.PostID != nil {
+		data["post_id"] = notification.PostID.String()
+	}
+
+	if notification.CommentID != nil {
+		data["comment_id"] = notification.CommentID.String()
+	}
+
+	return data
+}
+
+func (s notificationService) Retract(ctx context.Context, event Event) error {
+	notification, err := s.toNotification(ctx, event)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	return s.repo.DeleteUnreadNotifications(ctx, notification)
+}
+
+func (s notificationService) GetNotifications(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetNotificationsQueryRequest) ([]NotificationGroupResponse, error) {
+	groups, err := s.repo.GetNotificationGroups(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Latest distinct actors of each group, fetched all at once
+	var (
+		actorIdsPerGroup = make([][]string, len(groups))
+		allActorIds      []string
+	)
+	for i, group := range groups {
+		seen := make(map[string]bool)
+		for _, actorId := range strings.Split(group.ActorIDs, ",") {
+			if seen[actorId] {
+				continue
+			}
+			seen[actorId] = true
+			actorIdsPerGroup[i] = append(actorIdsPerGroup[i], actorId)
+			allActorIds = append(allActorIds, actorId)
+
+			if len(actorIdsPerGroup[i]) == MAXIMUM_ACTORS_PER_GROUP {
+				break
+			}
+		}
+	}
+
+	actors, err := s.repo.GetUsers(ctx, allActorIds)
+	if err != nil {
+		return nil, err
+	}
+
+	actorsById := make(map[string]internal.User, len(actors))
+	for _, actor := range actors {
+		actorsById[actor.ID] = actor
+	}
+
+	notifications := []NotificationGroupResponse{}
+	for i, group := range groups {
+		res := NotificationGroupResponse{
+			ID:          group.ID,
+			Type:        group.Type,
+			PostID:      group.PostID,
+			CommentID:   group.CommentID,
+			IsRead:      group.IsRead,
+			LatestAt:    group.LatestAt,
+			TotalActors: group.TotalActors,
+			Actors:      []internal.User{},
+		}
+
+		for _, actorId := range actorIdsPerGroup[i] {
+			if actor, ok := actorsById[actorId]; ok {
+				res.Actors = append(res.Actors, actor)
+			}
+		}
+
+		res.Message = groupMessage(res.Type, res.Actors, res.TotalActors)
+		notifications = append(notifications, res)
+	}
+
+	return notifications, nil
+}
+
+// e.g. "alice liked your post.", "alice and bob liked your post.", "alice and 5 others liked your post."
+func groupMessage(notificationType string, actors []internal.User, totalActors int) string {
+	var action string
+	switch notificationType {
+	case TYPE_POST_LIKE:
+		action = "liked your post."
+	case TYPE_COMMENT_LIKE:
+		action = "liked your comment."
+	case TYPE_COMMENT:
+		action = "commented on your post."
+	case TYPE_REPLY:
+		action = "replied to your comment."
+	case TYPE_FOLLOW:
+		action = "started following you."
+	}
+
+	if len(actors) == 0 {
+		return fmt.Sprintf("Someone %s", action)
+	}
+
+	others := totalActors - 1
+	switch {
+	case others <= 0:
+		return fmt.Sprintf("%s %s", actors[0].Username, action)
+	case others == 1 && len(actors) > 1:
+		return fmt.Sprintf("%s and %s %s", actors[0].Username, actors[1].Username, action)
+	case others == 1:
+		return fmt.Sprintf("%s and 1 other %s", actors[0].Username, action)
+	default:
+		return fmt.Sprintf("%s and %d others %s", actors[0].Username, others, action)
+	}
+}
+
+func (s notificationService) GetUnreadCount(ctx context.Context, reqUri internal.UserIdUriRequest) (UnreadCountResponse, error) {
+	count, err := s.repo.GetUnreadCount(ctx, reqUri.UserId)
+	if err != nil {
+		return UnreadCountResponse{}, err
+	}
+
+	return UnreadCountResponse{UnreadCount: count}, nil
+}
+
+func (s notificationService) MarkAllAsRead(ctx context.Context, reqUri internal.UserIdUriRequest) error {
+	return s.repo.MarkAllAsRead(ctx, reqUri.UserId)
+}
+
+func (s notificationService) MarkAsRead(ctx context.Context, reqUri NotificationUriRequest) error {
+	notificationId, _ := uuid.Parse(reqUri.NotificationId)
+	return s.repo.MarkGroupAsRead(ctx, reqUri.UserId, notificationId)
+}
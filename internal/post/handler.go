@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/notification"
+	"github.com/rrab-0/its-gram/internal/realtime"
 	"gorm.io/gorm"
 )
 
@@ -13,12 +15,18 @@ type Handler struct {
 	Service
 }
 
-func NewHandler(db *gorm.DB) Handler {
+func NewHandler(db *gorm.DB, notifier notification.Notifier, publisher realtime.Publisher) Handler {
 	return Handler{
-		Service: NewService(NewRepository(db)),
+		Service: NewService(NewRepository(db), notifier, publisher),
 	}
 }
 
+const (
+	MAXIMUM_LIMIT = 50
+	MINIMUM_LIMIT = 10
+	MINIMUM_PAGE  = 1
+)
+
 func (h Handler) CreatePost(ctx *gin.Context) {
 	var (
 		reqUri  internal.UserIdUriRequest
@@ -139,6 +147,12 @@ func (h Handler) GetPostById(ctx *gin.Context) {
 	postRes.Likes = post.Likes
 	postRes.TotalComments = totalComments
 
+	postRes.Type = post.Type
+	postRes.RepostOfID = post.RepostOfID
+	if post.RepostOfID != nil {
+		postRes.RepostOf = internal.NewRepostOfResponse(*post.RepostOfID, post.RepostOf)
+	}
+
 	for _, comment := range post.Comments {
 		if !comment.DeletedAt.Valid {
 			postRes.Comments = append(postRes.Comments, comment)
@@ -161,6 +175,92 @@ func (h Handler) GetPostById(ctx *gin.Context) {
 	})
 }
 
+func (h Handler) SearchPosts(ctx *gin.Context) {
+	var reqQuery SearchPostsQueryRequest
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	posts, err := h.Service.SearchPosts(ctx.Request.Context(), reqQuery)
+	if err != nil {
+		if err == ErrInvalidCursor {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Invalid request.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to search posts, posts not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to search posts.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Posts searched successfully.",
+		Data:    posts,
+	})
+}
+
+func (h Handler) SearchHashtags(ctx *gin.Context) {
+	var reqQuery SearchHashtagsQueryRequest
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	hashtags, err := h.Service.SearchHashtags(ctx.Request.Context(), reqQuery)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to search hashtags, hashtags not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to search hashtags.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Hashtags searched successfully.",
+		Data:    hashtags,
+	})
+}
+
 func (h Handler) DeletePost(ctx *gin.Context) {
 	var reqUri PostAndUserUriRequest
 	if err := ctx.ShouldBindUri(&reqUri); err != nil {
@@ -190,6 +290,14 @@ func (h Handler) DeletePost(ctx *gin.Context) {
 
 	err := h.Service.DeletePost(ctx.Request.Context(), reqUri)
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to delete post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
 			Message: "Failed to delete post.",
 			Error:   err.Error(),
@@ -229,8 +337,16 @@ func (h Handler) LikePost(ctx *gin.Context) {
 		return
 	}
 
-	err := h.Service.LikePost(ctx.Request.Context(), reqUri)
+	like, err := h.Service.LikePost(ctx.Request.Context(), reqUri)
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to like post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
 			Message: "Failed to like post.",
 			Error:   err.Error(),
@@ -240,6 +356,7 @@ func (h Handler) LikePost(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, internal.SuccessResponse{
 		Message: fmt.Sprintf("Post with id %v liked successfully", reqUri.PostId),
+		Data:    like,
 	})
 }
 
@@ -270,8 +387,16 @@ func (h Handler) UnlikePost(ctx *gin.Context) {
 		return
 	}
 
-	err := h.Service.UnlikePost(ctx.Request.Context(), reqUri)
+	like, err := h.Service.UnlikePost(ctx.Request.Context(), reqUri)
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to unlike post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
 			Message: "Failed to unlike post.",
 			Error:   err.Error(),
@@ -281,6 +406,7 @@ func (h Handler) UnlikePost(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, internal.SuccessResponse{
 		Message: fmt.Sprintf("Post with id %v unliked successfully", reqUri.PostId),
+		Data:    like,
 	})
 }
 
@@ -585,7 +711,7 @@ func (h Handler) LikeComment(ctx *gin.Context) {
 		return
 	}
 
-	err := h.Service.LikeComment(ctx.Request.Context(), reqUri)
+	like, err := h.Service.LikeComment(ctx.Request.Context(), reqUri)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
@@ -604,6 +730,7 @@ func (h Handler) LikeComment(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, internal.SuccessResponse{
 		Message: "Comment liked successfully.",
+		Data:    like,
 	})
 }
 
@@ -634,7 +761,7 @@ func (h Handler) UnlikeComment(ctx *gin.Context) {
 		return
 	}
 
-	err := h.Service.UnlikeComment(ctx.Request.Context(), reqUri)
+	like, err := h.Service.UnlikeComment(ctx.Request.Context(), reqUri)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
@@ -653,5 +780,1194 @@ func (h Handler) UnlikeComment(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, internal.SuccessResponse{
 		Message: "Removed like from comment successfully.",
+		Data:    like,
+	})
+}
+
+func (h Handler) RepostPost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to repost post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to repost post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	repost, err := h.Service.RepostPost(ctx.Request.Context(), reqUri)
+	if err != nil {
+		if err == ErrAlreadyReposted {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to repost post.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to repost post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to repost post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post reposted successfully.",
+		Data:    repost,
+	})
+}
+
+func (h Handler) UndoRepost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to undo repost.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to undo repost.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.UndoRepost(ctx.Request.Context(), reqUri); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to undo repost, repost not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to undo repost.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Repost undone successfully.",
+	})
+}
+
+func (h Handler) QuotePost(ctx *gin.Context) {
+	var (
+		reqUri  PostAndUserUriRequest
+		reqBody QuotePostRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to quote post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to quote post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	quote, err := h.Service.QuotePost(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to quote post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to quote post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post quoted successfully.",
+		Data:    quote,
+	})
+}
+
+func (h Handler) ReactPost(ctx *gin.Context) {
+	var (
+		reqUri  PostAndUserUriRequest
+		reqBody ReactRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to react to post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to react to post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	reactions, err := h.Service.ReactPost(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to react to post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to react to post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Reacted to post successfully.",
+		Data:    reactions,
+	})
+}
+
+func (h Handler) GetPostReactions(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch post's reactions.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch post's reactions.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	reactions, err := h.Service.GetPostReactions(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch post's reactions.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post's reactions fetched successfully.",
+		Data:    reactions,
+	})
+}
+
+func (h Handler) ReactComment(ctx *gin.Context) {
+	var (
+		reqUri  CommentAndUserUriRequest
+		reqBody ReactRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to react to comment.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to react to comment.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	reactions, err := h.Service.ReactComment(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to react to comment, comment not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to react to comment.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Reacted to comment successfully.",
+		Data:    reactions,
+	})
+}
+
+func (h Handler) GetCommentReactions(ctx *gin.Context) {
+	var reqUri CommentAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch comment's reactions.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch comment's reactions.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	reactions, err := h.Service.GetCommentReactions(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch comment's reactions.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Comment's reactions fetched successfully.",
+		Data:    reactions,
+	})
+}
+
+func (h Handler) CreateDraft(ctx *gin.Context) {
+	var (
+		reqUri  internal.UserIdUriRequest
+		reqBody CreateDraftRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to save draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to save draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	draft, err := h.Service.CreateDraft(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to save draft.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Draft saved successfully.",
+		Data:    draft,
+	})
+}
+
+func (h Handler) GetDrafts(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetDraftsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch drafts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch drafts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	drafts, err := h.Service.GetDrafts(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch drafts.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Drafts fetched successfully.",
+		Data:    drafts,
+	})
+}
+
+func (h Handler) UpdateDraft(ctx *gin.Context) {
+	var (
+		reqUri  PostAndUserUriRequest
+		reqBody UpdateDraftRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to update draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to update draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	draft, err := h.Service.UpdateDraft(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to update draft, draft not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to update draft.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Draft updated successfully.",
+		Data:    draft,
+	})
+}
+
+func (h Handler) PublishDraft(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to publish draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to publish draft.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	post, err := h.Service.PublishDraft(ctx.Request.Context(), reqUri)
+	if err != nil {
+		if err == ErrIncompleteDraft {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to publish draft.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to publish draft, draft not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to publish draft.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Draft published successfully.",
+		Data:    post,
+	})
+}
+
+func (h Handler) SchedulePost(ctx *gin.Context) {
+	var (
+		reqUri  internal.UserIdUriRequest
+		reqBody SchedulePostRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to schedule post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to schedule post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	post, err := h.Service.SchedulePost(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == ErrPublishAtPassed {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to schedule post.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to schedule post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post scheduled successfully.",
+		Data:    post,
+	})
+}
+
+func (h Handler) GetScheduledPosts(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetScheduledPostsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch scheduled posts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch scheduled posts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	posts, err := h.Service.GetScheduledPosts(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch scheduled posts.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Scheduled posts fetched successfully.",
+		Data:    posts,
+	})
+}
+
+func (h Handler) ReschedulePost(ctx *gin.Context) {
+	var (
+		reqUri  PostAndUserUriRequest
+		reqBody ReschedulePostRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to reschedule post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to reschedule post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	post, err := h.Service.ReschedulePost(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		if err == ErrPublishAtPassed {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, internal.ErrorResponse{
+				Message: "Failed to reschedule post.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to reschedule post, scheduled post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to reschedule post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post rescheduled successfully.",
+		Data:    post,
+	})
+}
+
+func (h Handler) CancelScheduledPost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to cancel scheduled post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to cancel scheduled post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	draft, err := h.Service.CancelScheduledPost(ctx.Request.Context(), reqUri)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to cancel scheduled post, scheduled post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to cancel scheduled post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Scheduled post moved to drafts successfully.",
+		Data:    draft,
+	})
+}
+
+func (h Handler) ArchivePost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to archive post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to archive post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.ArchivePost(ctx.Request.Context(), reqUri); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to archive post, post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to archive post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post archived successfully.",
+	})
+}
+
+func (h Handler) UnarchivePost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to unarchive post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to unarchive post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.UnarchivePost(ctx.Request.Context(), reqUri); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to unarchive post, archived post not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to unarchive post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post unarchived successfully.",
+	})
+}
+
+func (h Handler) GetArchivedPosts(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetArchivedPostsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch archived posts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch archived posts.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	posts, err := h.Service.GetArchivedPosts(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch archived posts.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Archived posts fetched successfully.",
+		Data:    posts,
+	})
+}
+
+func (h Handler) GetTrash(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery GetTrashQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	if reqQuery.Page < MINIMUM_PAGE {
+		reqQuery.Page = MINIMUM_PAGE
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch trash.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch trash.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	trash, err := h.Service.GetTrash(ctx.Request.Context(), reqUri, reqQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch trash.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Trash fetched successfully.",
+		Data:    trash,
+	})
+}
+
+func (h Handler) RestorePost(ctx *gin.Context) {
+	var reqUri PostAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to restore post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to restore post.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.RestorePost(ctx.Request.Context(), reqUri); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to restore post, post not found in trash.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to restore post.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Post restored successfully.",
+	})
+}
+
+func (h Handler) RestoreComment(ctx *gin.Context) {
+	var reqUri CommentAndUserUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to restore comment.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to restore comment.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if err := h.Service.RestoreComment(ctx.Request.Context(), reqUri); err != nil {
+		if err == ErrParentDeleted {
+			ctx.AbortWithStatusJSON(http.StatusConflict, internal.ErrorResponse{
+				Message: "Failed to restore comment, restore its post or parent comment first.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to restore comment, comment not found in trash.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to restore comment.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Comment restored successfully.",
 	})
 }
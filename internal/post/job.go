@@ -0,0 +1,43 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+)
+
+// Periodically publishes scheduled posts whose PublishAt has come.
+// Every instance runs it, the repository makes sure a post is only published once.
+type Job struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewJob(service Service, interval time.Duration) Job {
+	return Job{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j Job) Start(ctx context.Context) {
+	internal.RunEvery(ctx, j.interval, "publish scheduled posts", j.service.PublishDuePosts)
+}
+
+// Periodically removes trashed posts and comments past TRASH_RETENTION for good.
+type PurgeJob struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewPurgeJob(service Service, interval time.Duration) PurgeJob {
+	return PurgeJob{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j PurgeJob) Start(ctx context.Context) {
+	internal.RunEvery(ctx, j.interval, "purge trash", j.service.PurgeTrash)
+}
@@ -2,6 +2,7 @@ package post
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +10,46 @@ import (
 	"gorm.io/gorm"
 )
 
+// Post types
+const (
+	TYPE_POST   = "post"
+	TYPE_REPOST = "repost" // Only references the original post
+	TYPE_QUOTE  = "quote"  // Original post with the user's commentary as description
+)
+
+// Reactions to posts and comments, likes are REACTION_LIKE
+const (
+	REACTION_LIKE  = "like"  // 👍
+	REACTION_LOVE  = "love"  // ❤️
+	REACTION_HAHA  = "haha"  // 😂
+	REACTION_WOW   = "wow"   // 😮
+	REACTION_SAD   = "sad"   // 😢
+	REACTION_ANGRY = "angry" // 😡
+)
+
+var REACTIONS = []string{REACTION_LIKE, REACTION_LOVE, REACTION_HAHA, REACTION_WOW, REACTION_SAD, REACTION_ANGRY}
+
+var (
+	ErrAlreadyReposted = errors.New("post is already reposted")
+	ErrIncompleteDraft = errors.New("draft needs a picture and a title to be published")
+	ErrPublishAtPassed = errors.New("publish_at must be in the future")
+	ErrParentDeleted   = errors.New("post or parent comment of the comment is deleted")
+)
+
+const (
+	SCHEDULE_INTERVAL    = time.Minute // How often the scheduler publishes due posts
+	SCHEDULE_BATCH_LIMIT = 100
+
+	TRASH_RETENTION = 30 * 24 * time.Hour // How long deleted posts and comments can be restored
+	PURGE_INTERVAL  = time.Hour           // How often expired trash is permanently removed
+)
+
+// Trash item types
+const (
+	TRASH_TYPE_POST    = "post"
+	TRASH_TYPE_COMMENT = "comment"
+)
+
 type PostIdUriRequest struct {
 	PostId string `uri:"id" binding:"required,uuid"`
 }
@@ -24,6 +65,91 @@ type PostAndUserUriRequest struct {
 	PostId string `uri:"postId" binding:"required,uuid"`
 }
 
+// Drafts can be saved half-written, publishing checks what CreatePostRequest requires.
+type CreateDraftRequest struct {
+	PictureLink string `json:"picture_link"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Omitted fields are left as is.
+type UpdateDraftRequest struct {
+	PictureLink *string `json:"picture_link"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
+
+type GetDraftsQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type SchedulePostRequest struct {
+	PictureLink string    `json:"picture_link" binding:"required"`
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	PublishAt   time.Time `json:"publish_at" binding:"required"`
+}
+
+type ReschedulePostRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+type GetScheduledPostsQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type GetArchivedPostsQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type GetTrashQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type TrashQueryRes struct {
+	Type      string
+	ID        uuid.UUID
+	DeletedAt time.Time
+}
+
+// Either Post or Comment is set depending on Type.
+type TrashItemResponse struct {
+	Type      string            `json:"type"`
+	DeletedAt time.Time         `json:"deleted_at"`
+	ExpiresAt time.Time         `json:"expires_at"` // Permanently removed afterwards
+	Post      *internal.Post    `json:"post,omitempty"`
+	Comment   *internal.Comment `json:"comment,omitempty"`
+}
+
+type QuotePostRequest struct {
+	Description string `json:"description" binding:"required"`
+}
+
+type ReactRequest struct {
+	Reaction string `json:"reaction" binding:"required,oneof=like love haha wow sad angry"`
+}
+
+// State after a like or unlike, liking keeps a reaction other than REACTION_LIKE.
+type LikeResponse struct {
+	IsLiked    bool `json:"is_liked"`
+	TotalLikes int  `json:"total_likes"`
+}
+
+type ReactionCountQueryRes struct {
+	Reaction string
+	Total    int
+}
+
+type ReactionsResponse struct {
+	Counts   map[string]int `json:"counts"` // Every reaction, 0 if nobody used it
+	Total    int            `json:"total"`
+	Reaction *string        `json:"reaction"` // The user's, null if they didn't react
+}
+
 type CreateCommentRequest struct {
 	Description string `json:"description" binding:"required"`
 }
@@ -55,34 +181,155 @@ type GetCommentResponse struct {
 	Replies     []interface{}   `json:"replies"`
 }
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type SearchPostsQueryRequest struct {
+	Query  string `form:"q" binding:"required"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// Position of the last post of a search page,
+// posts are ordered by (rank, created_at, id) descending.
+type SearchPostsCursor struct {
+	Rank      float32
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type SearchPostsQueryRes struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Rank      float32
+}
+
+type SearchPostsResponse struct {
+	NextCursor string          `json:"next_cursor"`
+	Posts      []internal.Post `json:"posts"`
+}
+
+type SearchHashtagsQueryRequest struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+type HashtagQueryRes struct {
+	Name       string `json:"name"`
+	TotalPosts int    `json:"total_posts"`
+}
+
 type Repository interface {
 	GetPostById(ctx context.Context, id string) (post internal.Post, totalComments int, err error)
+	SearchPosts(ctx context.Context, query string, cursor *SearchPostsCursor, limit int) ([]internal.Post, []SearchPostsQueryRes, error)
+	SearchHashtags(ctx context.Context, query string, limit int) ([]HashtagQueryRes, error)
 	CreatePost(ctx context.Context, userId string, post internal.Post) (internal.Post, error)
 	DeletePost(ctx context.Context, userId string, postId uuid.UUID) error
-	LikePost(ctx context.Context, userId string, postId uuid.UUID) error
-	UnlikePost(ctx context.Context, userId string, postId uuid.UUID) error
+	GetPost(ctx context.Context, postId uuid.UUID) (internal.Post, error)
+	// ErrAlreadyReposted if the user already reposted it
+	CreateRepost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error)
+	GetRepost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error)
+
+	GetDraft(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error)
+	GetDrafts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error)
+	UpdateDraft(ctx context.Context, draft internal.Post) (internal.Post, error)
+	PublishDraft(ctx context.Context, userId string, postId uuid.UUID, publishedAt time.Time) error
+
+	GetScheduledPost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error)
+	GetScheduledPosts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error)
+	ReschedulePost(ctx context.Context, userId string, postId uuid.UUID, publishAt time.Time) error
+	CancelScheduledPost(ctx context.Context, userId string, postId uuid.UUID) error
+	ArchivePost(ctx context.Context, userId string, postId uuid.UUID) error
+	UnarchivePost(ctx context.Context, userId string, postId uuid.UUID) error
+	GetArchivedPosts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error)
+
+	// Only the user's own posts and comments deleted since "since" are in their trash
+	GetTrash(ctx context.Context, userId string, since time.Time, page, limit int) ([]TrashQueryRes, error)
+	GetDeletedPosts(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error)
+	GetDeletedComments(ctx context.Context, commentIds []uuid.UUID) ([]internal.Comment, error)
+	RestorePost(ctx context.Context, userId string, postId uuid.UUID, since time.Time) error
+	RestoreComment(ctx context.Context, userId string, commentId uuid.UUID, since time.Time) error
+	// Permanently removes posts and comments deleted before "before", with their likes
+	PurgeTrash(ctx context.Context, before time.Time) error
+
+	// Safe to run from many instances at once, each due post is published by only one of them
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]internal.Post, error)
+	// Both return false if the like was already there or already gone
+	LikePost(ctx context.Context, userId string, postId uuid.UUID) (bool, error)
+	UnlikePost(ctx context.Context, userId string, postId uuid.UUID) (bool, error)
+	GetPostCounts(ctx context.Context, postId uuid.UUID) (totalLikes int, totalComments int, err error)
+	GetFollowerIds(ctx context.Context, userId string) ([]string, error)
 
 	GetComment(ctx context.Context, commentId uuid.UUID) (internal.Comment, error)
-	CommentPost(ctx context.Context, userId, description string, postId uuid.UUID) error
+	GetCommentPostId(ctx context.Context, commentId uuid.UUID) (uuid.UUID, error)
+	CommentPost(ctx context.Context, userId, description string, postId uuid.UUID) (internal.Comment, error)
 	UncommentPost(ctx context.Context, userId string, commentId uuid.UUID) error
-	ReplyComment(ctx context.Context, userId, description string, postId, commentId uuid.UUID) error
+	ReplyComment(ctx context.Context, userId, description string, postId, commentId uuid.UUID) (internal.Comment, error)
 	RemoveReplyFromComment(ctx context.Context, userId string, commentId uuid.UUID) error
-	LikeComment(ctx context.Context, userId string, commentId uuid.UUID) error
-	UnlikeComment(ctx context.Context, userId string, commentId uuid.UUID) error
+	LikeComment(ctx context.Context, userId string, commentId uuid.UUID) (bool, error)
+	UnlikeComment(ctx context.Context, userId string, commentId uuid.UUID) (bool, error)
+	GetCommentLikeCount(ctx context.Context, commentId uuid.UUID) (int, error)
+
+	// Returns true if the user didn't react before, otherwise only changes the reaction
+	ReactPost(ctx context.Context, userId string, postId uuid.UUID, reaction string) (bool, error)
+	GetPostReactionCounts(ctx context.Context, postId uuid.UUID) ([]ReactionCountQueryRes, error)
+	GetPostReaction(ctx context.Context, userId string, postId uuid.UUID) (internal.PostReaction, error)
+	ReactComment(ctx context.Context, userId string, commentId uuid.UUID, reaction string) (bool, error)
+	GetCommentReactionCounts(ctx context.Context, commentId uuid.UUID) ([]ReactionCountQueryRes, error)
+	GetCommentReaction(ctx context.Context, userId string, commentId uuid.UUID) (internal.CommentReaction, error)
 }
 
 type Service interface {
 	GetPostById(ctx context.Context, reqUri PostIdUriRequest) (post internal.Post, totalComments int, err error)
+	SearchPosts(ctx context.Context, reqQuery SearchPostsQueryRequest) (SearchPostsResponse, error)
+	SearchHashtags(ctx context.Context, reqQuery SearchHashtagsQueryRequest) ([]HashtagQueryRes, error)
 	CreatePost(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreatePostRequest) (internal.Post, error)
 	DeletePost(ctx context.Context, reqUri PostAndUserUriRequest) error
-	LikePost(ctx context.Context, reqUri PostAndUserUriRequest) error
-	UnlikePost(ctx context.Context, reqUri PostAndUserUriRequest) error
+	RepostPost(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error)
+	UndoRepost(ctx context.Context, reqUri PostAndUserUriRequest) error
+	QuotePost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody QuotePostRequest) (internal.Post, error)
+
+	// Drafts are only visible to their owner, deleting one is the same as deleting a post
+	CreateDraft(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateDraftRequest) (internal.Post, error)
+	GetDrafts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetDraftsQueryRequest) ([]internal.Post, error)
+	UpdateDraft(ctx context.Context, reqUri PostAndUserUriRequest, reqBody UpdateDraftRequest) (internal.Post, error)
+	PublishDraft(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error)
+
+	// Scheduled posts are only visible to their owner until they're published
+	SchedulePost(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody SchedulePostRequest) (internal.Post, error)
+	GetScheduledPosts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetScheduledPostsQueryRequest) ([]internal.Post, error)
+	ReschedulePost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody ReschedulePostRequest) (internal.Post, error)
+	// Turns the post back into a draft
+	CancelScheduledPost(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error)
+	PublishDuePosts(ctx context.Context) error
+
+	// Archived posts are only visible to their owner, unarchiving puts them back in their original position
+	ArchivePost(ctx context.Context, reqUri PostAndUserUriRequest) error
+	UnarchivePost(ctx context.Context, reqUri PostAndUserUriRequest) error
+	GetArchivedPosts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetArchivedPostsQueryRequest) ([]internal.Post, error)
+
+	// Deleted posts and comments stay in the trash for TRASH_RETENTION
+	GetTrash(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetTrashQueryRequest) ([]TrashItemResponse, error)
+	RestorePost(ctx context.Context, reqUri PostAndUserUriRequest) error
+	RestoreComment(ctx context.Context, reqUri CommentAndUserUriRequest) error
+	PurgeTrash(ctx context.Context) error
+
+	// Lets anyone subscribe to realtime.PostTopic and realtime.PostCommentsTopic of published posts
+	AuthorizeTopic(ctx context.Context, userId, topic string) error
+	// Liking and unliking are idempotent, only existing posts and comments can be (un)liked
+	LikePost(ctx context.Context, reqUri PostAndUserUriRequest) (LikeResponse, error)
+	UnlikePost(ctx context.Context, reqUri PostAndUserUriRequest) (LikeResponse, error)
 
 	GetComment(ctx context.Context, reqUri GetCommentRequest) (internal.Comment, error)
 	CommentPost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody CreateCommentRequest) error
 	UncommentPost(ctx context.Context, reqUri CommentAndUserUriRequest) error
 	ReplyComment(ctx context.Context, reqUri ReplyCommentRequest, reqBody CreateCommentRequest) error
 	RemoveReplyFromComment(ctx context.Context, reqUri CommentAndUserUriRequest) error
-	LikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) error
-	UnlikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) error
+	LikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) (LikeResponse, error)
+	UnlikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) (LikeResponse, error)
+
+	// Unliking removes any reaction
+	ReactPost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody ReactRequest) (ReactionsResponse, error)
+	GetPostReactions(ctx context.Context, reqUri PostAndUserUriRequest) (ReactionsResponse, error)
+	ReactComment(ctx context.Context, reqUri CommentAndUserUriRequest, reqBody ReactRequest) (ReactionsResponse, error)
+	GetCommentReactions(ctx context.Context, reqUri CommentAndUserUriRequest) (ReactionsResponse, error)
 }
@@ -2,6 +2,9 @@ package post
 
 import (
 	"context"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rrab-0/its-gram/internal"
@@ -20,15 +23,62 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r gormRepository) CreatePost(ctx context.Context, userId string, post internal.Post) (internal.Post, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
 	post.UserID = userId
-	err := r.db.WithContext(ctx).Create(&post).Error
+	err := tx.Create(&post).Error
 	if err != nil {
+		tx.Rollback()
+		return internal.Post{}, err
+	}
+
+	if err := replaceHashtags(tx, post); err != nil {
+		tx.Rollback()
+		return internal.Post{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return internal.Post{}, err
 	}
 
 	return post, nil
 }
 
+// Same "#word" as Postgres' \w, letters of any language, digits and underscores.
+var hashtagRegexp = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+
+func extractHashtags(post internal.Post) []internal.PostHashtag {
+	var (
+		hashtags []internal.PostHashtag
+		seen     = make(map[string]bool)
+	)
+
+	for _, match := range hashtagRegexp.FindAllStringSubmatch(post.Title+" "+post.Description, -1) {
+		name := strings.ToLower(match[1])
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		hashtags = append(hashtags, internal.PostHashtag{PostID: post.ID, Name: name})
+	}
+
+	return hashtags
+}
+
+func replaceHashtags(tx *gorm.DB, post internal.Post) error {
+	if err := tx.Where("post_id = ?", post.ID).Delete(&internal.PostHashtag{}).Error; err != nil {
+		return err
+	}
+
+	hashtags := extractHashtags(post)
+	if len(hashtags) == 0 {
+		return nil
+	}
+
+	return tx.Create(&hashtags).Error
+}
+
 func (r gormRepository) GetPostById(ctx context.Context, id string) (internal.Post, int, error) {
 	var (
 		totalComments int
@@ -37,7 +87,8 @@ func (r gormRepository) GetPostById(ctx context.Context, id string) (internal.Po
 	)
 
 	// Lazy way to get total comments in post
-	err := tx.Unscoped().Preload("Comments").Where("id = ?", id).First(&post).Error
+	// Unscoped keeps deleted comments as placeholders, the post itself has to be there
+	err := tx.Unscoped().Preload("Comments").Where("id = ? AND status = ? AND deleted_at IS NULL", id, internal.POST_STATUS_PUBLISHED).First(&post).Error
 	if err != nil {
 		tx.Rollback()
 		return internal.Post{}, 0, err
@@ -52,7 +103,9 @@ func (r gormRepository) GetPostById(ctx context.Context, id string) (internal.Po
 		Preload("Comments.CreatedBy").
 		Preload("Comments.Likes").
 		Preload("Comments.Replies").
-		Where("id = ?", id).
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, internal.POST_STATUS_PUBLISHED).
 		First(&post).
 		Error
 	if err != nil {
@@ -67,38 +120,495 @@ func (r gormRepository) GetPostById(ctx context.Context, id string) (internal.Po
 	return post, totalComments, nil
 }
 
-func (r gormRepository) DeletePost(ctx context.Context, userId string, postId uuid.UUID) error {
+// Ranks posts matching query by ts_rank then recency,
+// websearch_to_tsquery supports "quoted phrases", OR and -excluded words.
+func (r gormRepository) SearchPosts(ctx context.Context, query string, cursor *SearchPostsCursor, limit int) ([]internal.Post, []SearchPostsQueryRes, error) {
 	var (
-		user internal.User
-		post internal.Post
+		ranks []SearchPostsQueryRes
+		posts []internal.Post
 	)
 
-	user.ID = userId
-	post.ID = postId
-	return r.db.WithContext(ctx).Model(&user).Association("Posts").Unscoped().Delete(&post)
+	rankQuery := r.db.
+		Table("posts").
+		Select("posts.id, posts.created_at, ts_rank(posts.search_vector, websearch_to_tsquery('simple', ?)) AS rank", query).
+		Where("posts.deleted_at IS NULL AND posts.status = ?", internal.POST_STATUS_PUBLISHED).
+		Where("posts.search_vector @@ websearch_to_tsquery('simple', ?)", query)
+
+	tx := r.db.WithContext(ctx).Table("(?) AS results", rankQuery)
+	if cursor != nil {
+		tx = tx.Where("(results.rank, results.created_at, results.id) < (?, ?, ?)", cursor.Rank, cursor.CreatedAt, cursor.ID)
+	}
+
+	err := tx.
+		Order("results.rank DESC, results.created_at DESC, results.id DESC").
+		Limit(limit).
+		Scan(&ranks).
+		Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(ranks) == 0 {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	postIds := make([]uuid.UUID, 0, len(ranks))
+	for _, rank := range ranks {
+		postIds = append(postIds, rank.ID)
+	}
+
+	err = r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Preload("Likes").
+		Preload("Comments").
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("id IN ?", postIds).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keep the order of the ranking query
+	postsById := make(map[uuid.UUID]internal.Post, len(posts))
+	for _, post := range posts {
+		postsById[post.ID] = post
+	}
+
+	posts = posts[:0]
+	for _, rank := range ranks {
+		if post, ok := postsById[rank.ID]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, ranks, nil
 }
 
-func (r gormRepository) LikePost(ctx context.Context, userId string, postId uuid.UUID) error {
-	var (
-		user internal.User
-		post internal.Post
-	)
+// Hashtags are every "#word" in posts' title and description, stored lowercased in post_hashtags.
+const searchHashtagsQuery = `
+SELECT post_hashtags.name, COUNT(*) AS total_posts
+FROM post_hashtags
+JOIN posts ON posts.id = post_hashtags.post_id AND posts.deleted_at IS NULL AND posts.status = ?
+WHERE post_hashtags.name LIKE ?
+GROUP BY post_hashtags.name
+ORDER BY total_posts DESC, post_hashtags.name
+LIMIT ?`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r gormRepository) SearchHashtags(ctx context.Context, query string, limit int) ([]HashtagQueryRes, error) {
+	var hashtags []HashtagQueryRes
+
+	prefix := likeEscaper.Replace(strings.ToLower(query)) + "%"
+	err := r.db.WithContext(ctx).Raw(searchHashtagsQuery, internal.POST_STATUS_PUBLISHED, prefix, limit).Scan(&hashtags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hashtags) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return hashtags, nil
+}
+
+func (r gormRepository) GetPost(ctx context.Context, postId uuid.UUID) (internal.Post, error) {
+	var post internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Where("id = ? AND status = ?", postId, internal.POST_STATUS_PUBLISHED).
+		First(&post).
+		Error
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return post, nil
+}
+
+// Concurrent reposts of the same post by a user lose on idx_posts_user_repost.
+func (r gormRepository) CreateRepost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error) {
+	repost := internal.Post{
+		UserID:     userId,
+		Type:       TYPE_REPOST,
+		RepostOfID: &postId,
+	}
+
+	res := r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "repost_of_id"}},
+			// Literal, a parameter would keep Postgres from matching the partial index
+			TargetWhere: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "type = '" + TYPE_REPOST + "' AND deleted_at IS NULL"},
+			}},
+			DoNothing: true,
+		}).
+		Create(&repost)
+	if res.Error != nil {
+		return internal.Post{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return internal.Post{}, ErrAlreadyReposted
+	}
+
+	return repost, nil
+}
+
+func (r gormRepository) GetRepost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error) {
+	var repost internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Where("user_id = ? AND repost_of_id = ? AND type = ?", userId, postId, TYPE_REPOST).
+		First(&repost).
+		Error
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return repost, nil
+}
+
+func (r gormRepository) GetDraft(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error) {
+	var draft internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, internal.POST_STATUS_DRAFT).
+		First(&draft).
+		Error
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return draft, nil
+}
+
+// Last edited first.
+func (r gormRepository) GetDrafts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error) {
+	var drafts []internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Where("user_id = ? AND status = ?", userId, internal.POST_STATUS_DRAFT).
+		Order("updated_at DESC, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&drafts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+func (r gormRepository) UpdateDraft(ctx context.Context, draft internal.Post) (internal.Post, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	res := tx.
+		Model(&internal.Post{}).
+		Where("id = ? AND status = ?", draft.ID, internal.POST_STATUS_DRAFT).
+		Updates(map[string]interface{}{
+			"picture_link": draft.PictureLink,
+			"title":        draft.Title,
+			"description":  draft.Description,
+		})
+	if res.Error != nil {
+		tx.Rollback()
+		return internal.Post{}, res.Error
+	}
+
+	// Published in the meantime, keep its hashtags
+	if res.RowsAffected > 0 {
+		if err := replaceHashtags(tx, draft); err != nil {
+			tx.Rollback()
+			return internal.Post{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Post{}, err
+	}
+
+	return r.GetDraft(ctx, draft.UserID, draft.ID)
+}
+
+// Only one of concurrent publishes goes through, the others don't find the draft anymore.
+func (r gormRepository) PublishDraft(ctx context.Context, userId string, postId uuid.UUID, publishedAt time.Time) error {
+	res := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, internal.POST_STATUS_DRAFT).
+		Updates(map[string]interface{}{
+			"status":     internal.POST_STATUS_PUBLISHED,
+			"created_at": publishedAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) GetScheduledPost(ctx context.Context, userId string, postId uuid.UUID) (internal.Post, error) {
+	var post internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, internal.POST_STATUS_SCHEDULED).
+		First(&post).
+		Error
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return post, nil
+}
+
+// Next to be published first.
+func (r gormRepository) GetScheduledPosts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error) {
+	var posts []internal.Post
 
-	user.ID = userId
-	post.ID = postId
+	err := r.db.
+		WithContext(ctx).
+		Where("user_id = ? AND status = ?", userId, internal.POST_STATUS_SCHEDULED).
+		Order("publish_at, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
 
-	return r.db.Model(&user).Association("LikedPosts").Append(&post)
+	return posts, nil
 }
 
-func (r gormRepository) UnlikePost(ctx context.Context, userId string, postId uuid.UUID) error {
+// Posts the scheduler is publishing are locked, the update waits then finds nothing to change.
+func (r gormRepository) ReschedulePost(ctx context.Context, userId string, postId uuid.UUID, publishAt time.Time) error {
+	res := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, internal.POST_STATUS_SCHEDULED).
+		Update("publish_at", publishAt)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) CancelScheduledPost(ctx context.Context, userId string, postId uuid.UUID) error {
+	res := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, internal.POST_STATUS_SCHEDULED).
+		Updates(map[string]interface{}{
+			"status":     internal.POST_STATUS_DRAFT,
+			"publish_at": nil,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) setPostStatus(ctx context.Context, userId string, postId uuid.UUID, from, to string) error {
+	res := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND user_id = ? AND status = ?", postId, userId, from).
+		Update("status", to)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) ArchivePost(ctx context.Context, userId string, postId uuid.UUID) error {
+	return r.setPostStatus(ctx, userId, postId, internal.POST_STATUS_PUBLISHED, internal.POST_STATUS_ARCHIVED)
+}
+
+func (r gormRepository) UnarchivePost(ctx context.Context, userId string, postId uuid.UUID) error {
+	return r.setPostStatus(ctx, userId, postId, internal.POST_STATUS_ARCHIVED, internal.POST_STATUS_PUBLISHED)
+}
+
+// Same order as on the profile.
+func (r gormRepository) GetArchivedPosts(ctx context.Context, userId string, page, limit int) ([]internal.Post, error) {
+	var posts []internal.Post
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Preload("Likes").
+		Preload("Comments").
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("user_id = ? AND status = ?", userId, internal.POST_STATUS_ARCHIVED).
+		Order("created_at DESC, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// SKIP LOCKED lets other instances publish the next due posts instead of waiting.
+func (r gormRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]internal.Post, error) {
 	var (
-		user internal.User
-		post internal.Post
+		posts []internal.Post
+		tx    = r.db.WithContext(ctx).Begin()
 	)
 
-	user.ID = userId
-	post.ID = postId
-	return r.db.WithContext(ctx).Model(&user).Association("LikedPosts").Delete(&post)
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND publish_at <= ?", internal.POST_STATUS_SCHEDULED, now).
+		Order("publish_at").
+		Limit(limit).
+		Find(&posts).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(posts) == 0 {
+		tx.Rollback()
+		return posts, nil
+	}
+
+	postIds := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		postIds = append(postIds, post.ID)
+	}
+
+	err = tx.
+		Model(&internal.Post{}).
+		Where("id IN ?", postIds).
+		Updates(map[string]interface{}{
+			"status":     internal.POST_STATUS_PUBLISHED,
+			"created_at": now,
+		}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	for i := range posts {
+		posts[i].Status = internal.POST_STATUS_PUBLISHED
+		posts[i].CreatedAt = now
+	}
+
+	return posts, nil
+}
+
+// Soft deleted, the post goes to the trash until PurgeTrash.
+func (r gormRepository) DeletePost(ctx context.Context, userId string, postId uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", postId, userId).Delete(&internal.Post{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// The conflict covers concurrent likes and keeps any other reaction.
+func (r gormRepository) LikePost(ctx context.Context, userId string, postId uuid.UUID) (bool, error) {
+	res := r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.PostReaction{UserID: userId, PostID: postId, Reaction: REACTION_LIKE})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r gormRepository) UnlikePost(ctx context.Context, userId string, postId uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ? AND post_id = ?", userId, postId).Delete(&internal.PostReaction{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r gormRepository) GetPostCounts(ctx context.Context, postId uuid.UUID) (int, int, error) {
+	var totalLikes, totalComments int64
+
+	err := r.db.WithContext(ctx).Table("user_liked_posts").Where("post_id = ?", postId).Count(&totalLikes).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = r.db.WithContext(ctx).Model(&internal.Comment{}).Where("post_id = ?", postId).Count(&totalComments).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(totalLikes), int(totalComments), nil
+}
+
+func (r gormRepository) GetFollowerIds(ctx context.Context, userId string) ([]string, error) {
+	var followerIds []string
+
+	err := r.db.WithContext(ctx).Table("user_followers").Where("user_id = ?", userId).Pluck("follower_id", &followerIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return followerIds, nil
+}
+
+func (r gormRepository) GetCommentPostId(ctx context.Context, commentId uuid.UUID) (uuid.UUID, error) {
+	var comment internal.Comment
+
+	err := r.db.WithContext(ctx).Unscoped().Select("id", "post_id").Where("id = ?", commentId).First(&comment).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return comment.PostID, nil
 }
 
 func (r gormRepository) GetComment(ctx context.Context, commentId uuid.UUID) (internal.Comment, error) {
@@ -106,13 +616,17 @@ func (r gormRepository) GetComment(ctx context.Context, commentId uuid.UUID) (in
 		comment internal.Comment
 	)
 
+	// Deleted comments are kept as placeholders, but not on posts others can't see
 	err := r.db.
 		WithContext(ctx).
 		Unscoped().
-		Preload(clause.Associations).
+		Preload("CreatedBy").
+		Preload("Likes").
+		Preload("Replies").
 		Preload("Replies.CreatedBy").
 		Preload("Replies.Likes").
 		Where("id = ?", commentId).
+		Where("EXISTS (SELECT 1 FROM posts WHERE posts.id = comments.post_id AND posts.deleted_at IS NULL AND posts.status = ?)", internal.POST_STATUS_PUBLISHED).
 		First(&comment).
 		Error
 	if err != nil {
@@ -122,21 +636,39 @@ func (r gormRepository) GetComment(ctx context.Context, commentId uuid.UUID) (in
 	return comment, nil
 }
 
-func (r gormRepository) CommentPost(ctx context.Context, userId, description string, postId uuid.UUID) error {
+func (r gormRepository) CommentPost(ctx context.Context, userId, description string, postId uuid.UUID) (internal.Comment, error) {
 	var comment internal.Comment
 	comment.UserID = userId
 	comment.PostCreatedInID = postId
 	comment.PostID = postId
 	comment.Description = description
 
-	return r.db.WithContext(ctx).Create(&comment).Error
+	if err := r.db.WithContext(ctx).Create(&comment).Error; err != nil {
+		return internal.Comment{}, err
+	}
+
+	if err := r.db.WithContext(ctx).Preload("CreatedBy").First(&comment, "id = ?", comment.ID).Error; err != nil {
+		return internal.Comment{}, err
+	}
+
+	return comment, nil
 }
 
+// Soft deleted like posts, only by its author.
 func (r gormRepository) UncommentPost(ctx context.Context, userId string, commentId uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", commentId).Delete(&internal.Comment{}).Error
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", commentId, userId).Delete(&internal.Comment{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
 
-func (r gormRepository) ReplyComment(ctx context.Context, userId, description string, postId, commentId uuid.UUID) error {
+func (r gormRepository) ReplyComment(ctx context.Context, userId, description string, postId, commentId uuid.UUID) (internal.Comment, error) {
 	var (
 		comment    internal.Comment
 		newComment internal.Comment
@@ -152,47 +684,380 @@ func (r gormRepository) ReplyComment(ctx context.Context, userId, description st
 	err := tx.Create(&newComment).Error
 	if err != nil {
 		tx.Rollback()
-		return err
+		return internal.Comment{}, err
 	}
 
 	comment.ID = commentId
 	err = tx.Model(&comment).Association("Replies").Append(&newComment)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return internal.Comment{}, err
+	}
+
+	if err := tx.Preload("CreatedBy").First(&newComment, "id = ?", newComment.ID).Error; err != nil {
+		tx.Rollback()
+		return internal.Comment{}, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		return err
+		return internal.Comment{}, err
 	}
 
-	return nil
+	return newComment, nil
 }
 
 func (r gormRepository) RemoveReplyFromComment(ctx context.Context, userId string, commentId uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", commentId).Delete(&internal.Comment{}).Error
+	return r.UncommentPost(ctx, userId, commentId)
 }
 
-func (r gormRepository) LikeComment(ctx context.Context, userId string, commentId uuid.UUID) error {
+func (r gormRepository) LikeComment(ctx context.Context, userId string, commentId uuid.UUID) (bool, error) {
+	res := r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.CommentReaction{UserID: userId, CommentID: commentId, Reaction: REACTION_LIKE})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r gormRepository) UnlikeComment(ctx context.Context, userId string, commentId uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ? AND comment_id = ?", userId, commentId).Delete(&internal.CommentReaction{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r gormRepository) GetCommentLikeCount(ctx context.Context, commentId uuid.UUID) (int, error) {
+	var totalLikes int64
+
+	if err := r.db.WithContext(ctx).Model(&internal.CommentReaction{}).Where("comment_id = ?", commentId).Count(&totalLikes).Error; err != nil {
+		return 0, err
+	}
+
+	return int(totalLikes), nil
+}
+
+func (r gormRepository) ReactPost(ctx context.Context, userId string, postId uuid.UUID, reaction string) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// Nothing inserted means the user already reacted, so the reaction is replaced instead
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&internal.PostReaction{UserID: userId, PostID: postId, Reaction: reaction})
+	if res.Error != nil {
+		tx.Rollback()
+		return false, res.Error
+	}
+
+	created := res.RowsAffected > 0
+	if !created {
+		if err := tx.Model(&internal.PostReaction{}).Where("user_id = ? AND post_id = ?", userId, postId).Update("reaction", reaction).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+func (r gormRepository) GetPostReactionCounts(ctx context.Context, postId uuid.UUID) ([]ReactionCountQueryRes, error) {
+	var counts []ReactionCountQueryRes
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.PostReaction{}).
+		Select("reaction, COUNT(*) AS total").
+		Where("post_id = ?", postId).
+		Group("reaction").
+		Scan(&counts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r gormRepository) GetPostReaction(ctx context.Context, userId string, postId uuid.UUID) (internal.PostReaction, error) {
+	var reaction internal.PostReaction
+
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND post_id = ?", userId, postId).First(&reaction).Error; err != nil {
+		return internal.PostReaction{}, err
+	}
+
+	return reaction, nil
+}
+
+func (r gormRepository) ReactComment(ctx context.Context, userId string, commentId uuid.UUID, reaction string) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// Nothing inserted means the user already reacted, so the reaction is replaced instead
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&internal.CommentReaction{UserID: userId, CommentID: commentId, Reaction: reaction})
+	if res.Error != nil {
+		tx.Rollback()
+		return false, res.Error
+	}
+
+	created := res.RowsAffected > 0
+	if !created {
+		if err := tx.Model(&internal.CommentReaction{}).Where("user_id = ? AND comment_id = ?", userId, commentId).Update("reaction", reaction).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+func (r gormRepository) GetCommentReactionCounts(ctx context.Context, commentId uuid.UUID) ([]ReactionCountQueryRes, error) {
+	var counts []ReactionCountQueryRes
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.CommentReaction{}).
+		Select("reaction, COUNT(*) AS total").
+		Where("comment_id = ?", commentId).
+		Group("reaction").
+		Scan(&counts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r gormRepository) GetCommentReaction(ctx context.Context, userId string, commentId uuid.UUID) (internal.CommentReaction, error) {
+	var reaction internal.CommentReaction
+
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND comment_id = ?", userId, commentId).First(&reaction).Error; err != nil {
+		return internal.CommentReaction{}, err
+	}
+
+	return reaction, nil
+}
+
+// Latest deleted first.
+func (r gormRepository) GetTrash(ctx context.Context, userId string, since time.Time, page, limit int) ([]TrashQueryRes, error) {
+	var trash []TrashQueryRes
+
+	err := r.db.WithContext(ctx).Raw(`
+	SELECT * FROM (
+		SELECT @postType AS type, posts.id, posts.deleted_at
+		FROM posts
+		WHERE posts.user_id = @userId AND posts.deleted_at >= @since
+		UNION ALL
+		SELECT @commentType AS type, comments.id, comments.deleted_at
+		FROM comments
+		WHERE comments.user_id = @userId AND comments.deleted_at >= @since
+	) AS trash
+	ORDER BY trash.deleted_at DESC, trash.id
+	OFFSET @offset
+	LIMIT @limit
+	`, map[string]interface{}{
+		"postType":    TRASH_TYPE_POST,
+		"commentType": TRASH_TYPE_COMMENT,
+		"userId":      userId,
+		"since":       since,
+		"offset":      (page - 1) * limit,
+		"limit":       limit,
+	}).Scan(&trash).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return trash, nil
+}
+
+func (r gormRepository) GetDeletedPosts(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error) {
+	var posts []internal.Post
+
+	if len(postIds) == 0 {
+		return posts, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Unscoped().
+		Preload("CreatedBy").
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("id IN ?", postIds).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r gormRepository) GetDeletedComments(ctx context.Context, commentIds []uuid.UUID) ([]internal.Comment, error) {
+	var comments []internal.Comment
+
+	if len(commentIds) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Unscoped().
+		Preload("CreatedBy").
+		Where("id IN ?", commentIds).
+		Find(&comments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r gormRepository) RestorePost(ctx context.Context, userId string, postId uuid.UUID, since time.Time) error {
+	res := r.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&internal.Post{}).
+		Where("id = ? AND user_id = ? AND deleted_at >= ?", postId, userId, since).
+		Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Only while its post, and its parent comment for replies, aren't deleted. They're locked
+// until the restore is committed so they can't be deleted in the meantime.
+func (r gormRepository) RestoreComment(ctx context.Context, userId string, commentId uuid.UUID, since time.Time) error {
 	var (
-		user    internal.User
 		comment internal.Comment
+		tx      = r.db.WithContext(ctx).Begin()
 	)
 
-	user.ID = userId
-	comment.ID = commentId
+	err := tx.Unscoped().Where("id = ? AND user_id = ? AND deleted_at >= ?", commentId, userId, since).First(&comment).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").Where("id = ?", comment.PostID).First(&internal.Post{}).Error
+	if err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return ErrParentDeleted
+		}
+		return err
+	}
+
+	if comment.ParentID != nil {
+		err = tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").Where("id = ?", *comment.ParentID).First(&internal.Comment{}).Error
+		if err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				return ErrParentDeleted
+			}
+			return err
+		}
+	}
 
-	return r.db.Model(&user).Association("LikedComments").Append(&comment)
+	err = tx.Unscoped().Model(&comment).Update("deleted_at", nil).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
 }
 
-func (r gormRepository) UnlikeComment(ctx context.Context, userId string, commentId uuid.UUID) error {
+// Comments of purged posts go with them. Other deleted comments that still have replies are
+// kept as placeholders, they're purged in a later run once their replies are gone.
+// Notifications about purged posts and comments are removed, shared posts are emptied.
+func (r gormRepository) PurgeTrash(ctx context.Context, before time.Time) error {
 	var (
-		user    internal.User
-		comment internal.Comment
+		postIds    []uuid.UUID
+		commentIds []uuid.UUID
+		tx         = r.db.WithContext(ctx).Begin()
 	)
 
-	user.ID = userId
-	comment.ID = commentId
+	err := tx.Unscoped().Model(&internal.Post{}).Where("deleted_at < ?", before).Pluck("id", &postIds).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	return r.db.Model(&user).Association("LikedComments").Delete(&comment)
+	err = tx.
+		Unscoped().
+		Model(&internal.Comment{}).
+		Where("post_id IN ?", postIds).
+		Or("deleted_at < ? AND NOT EXISTS (SELECT 1 FROM comments AS replies WHERE replies.parent_id = comments.id)", before).
+		Pluck("id", &commentIds).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(postIds) == 0 && len(commentIds) == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	err = tx.
+		Unscoped().
+		Model(&internal.Message{}).
+		Where("post_id IN ?", postIds).
+		UpdateColumn("post_id", nil).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Likes and references first, the foreign keys would stop the deletes otherwise
+	deletes := []struct {
+		model interface{}
+		query string
+		args  []interface{}
+	}{
+		{&internal.Notification{}, "post_id IN ? OR comment_id IN ?", []interface{}{postIds, commentIds}},
+		{&internal.CommentReaction{}, "comment_id IN ?", []interface{}{commentIds}},
+		{&internal.Comment{}, "id IN ?", []interface{}{commentIds}},
+		{&internal.PostReaction{}, "post_id IN ?", []interface{}{postIds}},
+		{&internal.Bookmark{}, "post_id IN ?", []interface{}{postIds}},
+		{&internal.PostHashtag{}, "post_id IN ?", []interface{}{postIds}},
+		{&internal.BookmarkCollectionPost{}, "post_id IN ?", []interface{}{postIds}},
+		{&internal.Post{}, "id IN ?", []interface{}{postIds}},
+	}
+
+	for _, d := range deletes {
+		if err := tx.Unscoped().Where(d.query, d.args...).Delete(d.model).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -2,23 +2,104 @@ package post
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/notification"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"gorm.io/gorm"
 )
 
 type postService struct {
-	repo Repository
+	repo      Repository
+	notifier  notification.Notifier
+	publisher realtime.Publisher
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, notifier notification.Notifier, publisher realtime.Publisher) Service {
 	return postService{
-		repo: repo,
+		repo:      repo,
+		notifier:  notifier,
+		publisher: publisher,
+	}
+}
+
+// Notifications are best effort, failing to send one shouldn't fail the action itself.
+func (s postService) notify(ctx context.Context, event notification.Event) {
+	if err := s.notifier.Notify(ctx, event); err != nil {
+		log.Printf("ERROR: Failed to send %v notification: %v", event.Type, err.Error())
+	}
+}
+
+func (s postService) retract(ctx context.Context, event notification.Event) {
+	if err := s.notifier.Retract(ctx, event); err != nil {
+		log.Printf("ERROR: Failed to retract %v notification: %v", event.Type, err.Error())
+	}
+}
+
+// Realtime events are best effort as well, clients refetch when they reconnect.
+func (s postService) publish(ctx context.Context, event realtime.Event) {
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("ERROR: Failed to publish %v event: %v", event.Type, err.Error())
+	}
+}
+
+func (s postService) publishPostCounts(ctx context.Context, postId uuid.UUID) {
+	totalLikes, totalComments, err := s.repo.GetPostCounts(ctx, postId)
+	if err != nil {
+		log.Printf("ERROR: Failed to count likes and comments of post %v: %v", postId, err.Error())
+		return
+	}
+
+	s.publish(ctx, realtime.Event{
+		Topic: realtime.PostTopic(postId.String()),
+		Type:  realtime.TYPE_POST_COUNTS,
+		Data: realtime.PostCountsEventData{
+			PostID:        postId.String(),
+			TotalLikes:    totalLikes,
+			TotalComments: totalComments,
+		},
+	})
+}
+
+func (s postService) publishComment(ctx context.Context, comment internal.Comment) {
+	s.publish(ctx, realtime.Event{
+		Topic: realtime.PostCommentsTopic(comment.PostID.String()),
+		Type:  realtime.TYPE_COMMENT,
+		Data:  comment,
+	})
+}
+
+// Hints followers' clients that their feed has something new.
+func (s postService) publishFeedItem(ctx context.Context, post internal.Post) {
+	followerIds, err := s.repo.GetFollowerIds(ctx, post.UserID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch followers of user %v: %v", post.UserID, err.Error())
+		return
+	}
+
+	for _, followerId := range followerIds {
+		s.publish(ctx, realtime.Event{
+			Topic: realtime.UserTopic(followerId),
+			Type:  realtime.TYPE_FEED_ITEM,
+			Data: realtime.FeedItemEventData{
+				PostID:    post.ID.String(),
+				CreatedBy: post.UserID,
+			},
+		})
 	}
 }
 
 func (s postService) CreatePost(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreatePostRequest) (internal.Post, error) {
 	post := internal.Post{
+		Type:        TYPE_POST,
+		Status:      internal.POST_STATUS_PUBLISHED,
 		PictureLink: reqBody.PictureLink,
 		Title:       reqBody.Title,
 		Description: reqBody.Description,
@@ -29,6 +110,7 @@ func (s postService) CreatePost(ctx context.Context, reqUri internal.UserIdUriRe
 		return internal.Post{}, err
 	}
 
+	s.publishFeedItem(ctx, post)
 	return post, nil
 }
 
@@ -41,19 +123,493 @@ func (s postService) GetPostById(ctx context.Context, reqUri PostIdUriRequest) (
 	return post, totalComments, nil
 }
 
+func (s postService) SearchPosts(ctx context.Context, reqQuery SearchPostsQueryRequest) (SearchPostsResponse, error) {
+	var cursor *SearchPostsCursor
+	if reqQuery.Cursor != "" {
+		decoded, err := decodeSearchPostsCursor(reqQuery.Cursor)
+		if err != nil {
+			return SearchPostsResponse{}, err
+		}
+		cursor = &decoded
+	}
+
+	posts, ranks, err := s.repo.SearchPosts(ctx, reqQuery.Query, cursor, reqQuery.Limit)
+	if err != nil {
+		return SearchPostsResponse{}, err
+	}
+
+	// Less than a full page means there is nothing left to fetch
+	var nextCursor string
+	if len(ranks) == reqQuery.Limit {
+		last := ranks[len(ranks)-1]
+		nextCursor = encodeSearchPostsCursor(SearchPostsCursor{
+			Rank:      last.Rank,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	return SearchPostsResponse{
+		NextCursor: nextCursor,
+		Posts:      posts,
+	}, nil
+}
+
+func (s postService) SearchHashtags(ctx context.Context, reqQuery SearchHashtagsQueryRequest) ([]HashtagQueryRes, error) {
+	// "#" alone would match every hashtag
+	query := strings.TrimSpace(strings.TrimPrefix(reqQuery.Query, "#"))
+	if query == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	hashtags, err := s.repo.SearchHashtags(ctx, query, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashtags, nil
+}
+
+// Cursor is "rank|created_at|id" encoded in base64 so clients treat it as opaque.
+func encodeSearchPostsCursor(cursor SearchPostsCursor) string {
+	raw := fmt.Sprintf("%s|%s|%s",
+		strconv.FormatFloat(float64(cursor.Rank), 'g', -1, 32),
+		cursor.CreatedAt.Format(time.RFC3339Nano),
+		cursor.ID.String(),
+	)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchPostsCursor(encoded string) (SearchPostsCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return SearchPostsCursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return SearchPostsCursor{}, ErrInvalidCursor
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return SearchPostsCursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return SearchPostsCursor{}, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return SearchPostsCursor{}, ErrInvalidCursor
+	}
+
+	return SearchPostsCursor{
+		Rank:      float32(rank),
+		CreatedAt: createdAt,
+		ID:        id,
+	}, nil
+}
+
 func (s postService) DeletePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
 	postId, _ := uuid.Parse(reqUri.PostId)
 	return s.repo.DeletePost(ctx, reqUri.UserId, postId)
 }
 
-func (s postService) LikePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
+// Reposting a repost reposts its original, quotes are kept as is.
+func (s postService) getRepostTarget(ctx context.Context, postId uuid.UUID) (internal.Post, error) {
+	post, err := s.repo.GetPost(ctx, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	if post.Type != TYPE_REPOST || post.RepostOfID == nil {
+		return post, nil
+	}
+
+	return s.repo.GetPost(ctx, *post.RepostOfID)
+}
+
+func (s postService) RepostPost(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	original, err := s.getRepostTarget(ctx, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	repost, err := s.repo.CreateRepost(ctx, reqUri.UserId, original.ID)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	s.publishFeedItem(ctx, repost)
+	repost.RepostOf = &original
+	return repost, nil
+}
+
+func (s postService) UndoRepost(ctx context.Context, reqUri PostAndUserUriRequest) error {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	// Works even if the original is deleted by now
+	repost, err := s.repo.GetRepost(ctx, reqUri.UserId, postId)
+	if err == gorm.ErrRecordNotFound {
+		// postId may be someone else's repost of the original
+		original, err := s.getRepostTarget(ctx, postId)
+		if err != nil {
+			return err
+		}
+
+		repost, err = s.repo.GetRepost(ctx, reqUri.UserId, original.ID)
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return s.repo.DeletePost(ctx, reqUri.UserId, repost.ID)
+}
+
+func (s postService) QuotePost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody QuotePostRequest) (internal.Post, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	original, err := s.getRepostTarget(ctx, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	quote, err := s.repo.CreatePost(ctx, reqUri.UserId, internal.Post{
+		Type:        TYPE_QUOTE,
+		Description: reqBody.Description,
+		RepostOfID:  &original.ID,
+	})
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	s.publishFeedItem(ctx, quote)
+	quote.RepostOf = &original
+	return quote, nil
+}
+
+func (s postService) CreateDraft(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateDraftRequest) (internal.Post, error) {
+	draft, err := s.repo.CreatePost(ctx, reqUri.UserId, internal.Post{
+		Type:        TYPE_POST,
+		Status:      internal.POST_STATUS_DRAFT,
+		PictureLink: reqBody.PictureLink,
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return draft, nil
+}
+
+func (s postService) GetDrafts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetDraftsQueryRequest) ([]internal.Post, error) {
+	drafts, err := s.repo.GetDrafts(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+func (s postService) UpdateDraft(ctx context.Context, reqUri PostAndUserUriRequest, reqBody UpdateDraftRequest) (internal.Post, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	draft, err := s.repo.GetDraft(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	if reqBody.PictureLink != nil {
+		draft.PictureLink = *reqBody.PictureLink
+	}
+
+	if reqBody.Title != nil {
+		draft.Title = *reqBody.Title
+	}
+
+	if reqBody.Description != nil {
+		draft.Description = *reqBody.Description
+	}
+
+	draft, err = s.repo.UpdateDraft(ctx, draft)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return draft, nil
+}
+
+// The post counts as created when it's published, so it shows up at the top of feeds.
+func (s postService) PublishDraft(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	draft, err := s.repo.GetDraft(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	if draft.PictureLink == "" || draft.Title == "" {
+		return internal.Post{}, ErrIncompleteDraft
+	}
+
+	if err := s.repo.PublishDraft(ctx, reqUri.UserId, postId, time.Now()); err != nil {
+		return internal.Post{}, err
+	}
+
+	post, err := s.repo.GetPost(ctx, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	s.publishFeedItem(ctx, post)
+	return post, nil
+}
+
+func (s postService) SchedulePost(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody SchedulePostRequest) (internal.Post, error) {
+	if !reqBody.PublishAt.After(time.Now()) {
+		return internal.Post{}, ErrPublishAtPassed
+	}
+
+	post, err := s.repo.CreatePost(ctx, reqUri.UserId, internal.Post{
+		Type:        TYPE_POST,
+		Status:      internal.POST_STATUS_SCHEDULED,
+		PublishAt:   &reqBody.PublishAt,
+		PictureLink: reqBody.PictureLink,
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return post, nil
+}
+
+func (s postService) GetScheduledPosts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetScheduledPostsQueryRequest) ([]internal.Post, error) {
+	posts, err := s.repo.GetScheduledPosts(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (s postService) ReschedulePost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody ReschedulePostRequest) (internal.Post, error) {
+	if !reqBody.PublishAt.After(time.Now()) {
+		return internal.Post{}, ErrPublishAtPassed
+	}
+
+	postId, _ := uuid.Parse(reqUri.PostId)
+	if err := s.repo.ReschedulePost(ctx, reqUri.UserId, postId, reqBody.PublishAt); err != nil {
+		return internal.Post{}, err
+	}
+
+	post, err := s.repo.GetScheduledPost(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return post, nil
+}
+
+func (s postService) CancelScheduledPost(ctx context.Context, reqUri PostAndUserUriRequest) (internal.Post, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+	if err := s.repo.CancelScheduledPost(ctx, reqUri.UserId, postId); err != nil {
+		return internal.Post{}, err
+	}
+
+	draft, err := s.repo.GetDraft(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return internal.Post{}, err
+	}
+
+	return draft, nil
+}
+
+// Publishes in batches until nothing is due, followers are told like for a new post.
+func (s postService) PublishDuePosts(ctx context.Context) error {
+	for {
+		posts, err := s.repo.PublishDuePosts(ctx, time.Now(), SCHEDULE_BATCH_LIMIT)
+		if err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			s.publishFeedItem(ctx, post)
+		}
+
+		if len(posts) < SCHEDULE_BATCH_LIMIT {
+			return nil
+		}
+	}
+}
+
+func (s postService) ArchivePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
 	postId, _ := uuid.Parse(reqUri.PostId)
-	return s.repo.LikePost(ctx, reqUri.UserId, postId)
+	return s.repo.ArchivePost(ctx, reqUri.UserId, postId)
 }
 
-func (s postService) UnlikePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
+func (s postService) UnarchivePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
 	postId, _ := uuid.Parse(reqUri.PostId)
-	return s.repo.UnlikePost(ctx, reqUri.UserId, postId)
+	return s.repo.UnarchivePost(ctx, reqUri.UserId, postId)
+}
+
+func (s postService) GetArchivedPosts(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetArchivedPostsQueryRequest) ([]internal.Post, error) {
+	posts, err := s.repo.GetArchivedPosts(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (s postService) GetTrash(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetTrashQueryRequest) ([]TrashItemResponse, error) {
+	trash, err := s.repo.GetTrash(ctx, reqUri.UserId, time.Now().Add(-TRASH_RETENTION), reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var postIds, commentIds []uuid.UUID
+	for _, item := range trash {
+		if item.Type == TRASH_TYPE_POST {
+			postIds = append(postIds, item.ID)
+		} else {
+			commentIds = append(commentIds, item.ID)
+		}
+	}
+
+	posts, err := s.repo.GetDeletedPosts(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetDeletedComments(ctx, commentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	postsById := make(map[uuid.UUID]*internal.Post, len(posts))
+	for i := range posts {
+		postsById[posts[i].ID] = &posts[i]
+	}
+
+	commentsById := make(map[uuid.UUID]*internal.Comment, len(comments))
+	for i := range comments {
+		commentsById[comments[i].ID] = &comments[i]
+	}
+
+	items := make([]TrashItemResponse, 0, len(trash))
+	for _, item := range trash {
+		res := TrashItemResponse{
+			Type:      item.Type,
+			DeletedAt: item.DeletedAt,
+			ExpiresAt: item.DeletedAt.Add(TRASH_RETENTION),
+		}
+
+		if item.Type == TRASH_TYPE_POST {
+			res.Post = postsById[item.ID]
+		} else {
+			res.Comment = commentsById[item.ID]
+		}
+
+		// Purged in the meantime
+		if res.Post == nil && res.Comment == nil {
+			continue
+		}
+
+		items = append(items, res)
+	}
+
+	return items, nil
+}
+
+func (s postService) RestorePost(ctx context.Context, reqUri PostAndUserUriRequest) error {
+	postId, _ := uuid.Parse(reqUri.PostId)
+	return s.repo.RestorePost(ctx, reqUri.UserId, postId, time.Now().Add(-TRASH_RETENTION))
+}
+
+func (s postService) RestoreComment(ctx context.Context, reqUri CommentAndUserUriRequest) error {
+	commentId, _ := uuid.Parse(reqUri.CommentId)
+	if err := s.repo.RestoreComment(ctx, reqUri.UserId, commentId, time.Now().Add(-TRASH_RETENTION)); err != nil {
+		return err
+	}
+
+	s.publishCommentPostCounts(ctx, commentId)
+	return nil
+}
+
+func (s postService) PurgeTrash(ctx context.Context) error {
+	return s.repo.PurgeTrash(ctx, time.Now().Add(-TRASH_RETENTION))
+}
+
+func (s postService) LikePost(ctx context.Context, reqUri PostAndUserUriRequest) (LikeResponse, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	// Soft deleted posts are not found either
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		return LikeResponse{}, err
+	}
+
+	liked, err := s.repo.LikePost(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	// Double taps only notify once
+	if liked {
+		s.publishPostCounts(ctx, postId)
+		s.notify(ctx, notification.Event{
+			Type:    notification.TYPE_POST_LIKE,
+			ActorID: reqUri.UserId,
+			PostID:  &postId,
+		})
+	}
+
+	return s.getPostLikeState(ctx, postId, true)
+}
+
+func (s postService) UnlikePost(ctx context.Context, reqUri PostAndUserUriRequest) (LikeResponse, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		return LikeResponse{}, err
+	}
+
+	unliked, err := s.repo.UnlikePost(ctx, reqUri.UserId, postId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	if unliked {
+		s.publishPostCounts(ctx, postId)
+		s.retract(ctx, notification.Event{
+			Type:    notification.TYPE_POST_LIKE,
+			ActorID: reqUri.UserId,
+			PostID:  &postId,
+		})
+	}
+
+	return s.getPostLikeState(ctx, postId, false)
+}
+
+func (s postService) getPostLikeState(ctx context.Context, postId uuid.UUID, isLiked bool) (LikeResponse, error) {
+	totalLikes, _, err := s.repo.GetPostCounts(ctx, postId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	return LikeResponse{
+		IsLiked:    isLiked,
+		TotalLikes: totalLikes,
+	}, nil
 }
 
 func (s postService) GetComment(ctx context.Context, reqUri GetCommentRequest) (internal.Comment, error) {
@@ -69,31 +625,285 @@ func (s postService) GetComment(ctx context.Context, reqUri GetCommentRequest) (
 
 func (s postService) CommentPost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody CreateCommentRequest) error {
 	postId, _ := uuid.Parse(reqUri.PostId)
-	return s.repo.CommentPost(ctx, reqUri.UserId, reqBody.Description, postId)
+
+	// Deleted, draft, scheduled and archived posts are not found
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		return err
+	}
+
+	comment, err := s.repo.CommentPost(ctx, reqUri.UserId, reqBody.Description, postId)
+	if err != nil {
+		return err
+	}
+
+	s.publishComment(ctx, comment)
+	s.publishPostCounts(ctx, postId)
+	s.notify(ctx, notification.Event{
+		Type:    notification.TYPE_COMMENT,
+		ActorID: reqUri.UserId,
+		PostID:  &postId,
+	})
+	return nil
 }
 
 func (s postService) UncommentPost(ctx context.Context, reqUri CommentAndUserUriRequest) error {
 	commentId, _ := uuid.Parse(reqUri.CommentId)
-	return s.repo.UncommentPost(ctx, reqUri.UserId, commentId)
+	if err := s.repo.UncommentPost(ctx, reqUri.UserId, commentId); err != nil {
+		return err
+	}
+
+	s.publishCommentPostCounts(ctx, commentId)
+	return nil
+}
+
+func (s postService) publishCommentPostCounts(ctx context.Context, commentId uuid.UUID) {
+	postId, err := s.repo.GetCommentPostId(ctx, commentId)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch post of comment %v: %v", commentId, err.Error())
+		return
+	}
+
+	s.publishPostCounts(ctx, postId)
 }
 
 func (s postService) ReplyComment(ctx context.Context, reqUri ReplyCommentRequest, reqBody CreateCommentRequest) error {
 	postId, _ := uuid.Parse(reqUri.PostId)
 	commentId, _ := uuid.Parse(reqUri.CommentId)
-	return s.repo.ReplyComment(ctx, reqUri.UserId, reqBody.Description, postId, commentId)
+
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		return err
+	}
+
+	comment, err := s.repo.GetComment(ctx, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.DeletedAt.Valid || comment.PostID != postId {
+		return gorm.ErrRecordNotFound
+	}
+
+	reply, err := s.repo.ReplyComment(ctx, reqUri.UserId, reqBody.Description, postId, commentId)
+	if err != nil {
+		return err
+	}
+
+	s.publishComment(ctx, reply)
+	s.publishPostCounts(ctx, postId)
+	s.notify(ctx, notification.Event{
+		Type:      notification.TYPE_REPLY,
+		ActorID:   reqUri.UserId,
+		PostID:    &postId,
+		CommentID: &commentId,
+	})
+	return nil
 }
 
 func (s postService) RemoveReplyFromComment(ctx context.Context, reqUri CommentAndUserUriRequest) error {
 	commentId, _ := uuid.Parse(reqUri.CommentId)
-	return s.repo.RemoveReplyFromComment(ctx, reqUri.UserId, commentId)
+	if err := s.repo.RemoveReplyFromComment(ctx, reqUri.UserId, commentId); err != nil {
+		return err
+	}
+
+	s.publishCommentPostCounts(ctx, commentId)
+	return nil
+}
+
+// Deleted comments are kept for their replies, they can't be (un)liked anymore.
+func (s postService) getLikeableComment(ctx context.Context, commentId uuid.UUID) error {
+	comment, err := s.repo.GetComment(ctx, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (s postService) LikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) (LikeResponse, error) {
+	commentId, _ := uuid.Parse(reqUri.CommentId)
+
+	if err := s.getLikeableComment(ctx, commentId); err != nil {
+		return LikeResponse{}, err
+	}
+
+	liked, err := s.repo.LikeComment(ctx, reqUri.UserId, commentId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	if liked {
+		s.notify(ctx, notification.Event{
+			Type:      notification.TYPE_COMMENT_LIKE,
+			ActorID:   reqUri.UserId,
+			CommentID: &commentId,
+		})
+	}
+
+	return s.getCommentLikeState(ctx, commentId, true)
+}
+
+func (s postService) UnlikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) (LikeResponse, error) {
+	commentId, _ := uuid.Parse(reqUri.CommentId)
+
+	if err := s.getLikeableComment(ctx, commentId); err != nil {
+		return LikeResponse{}, err
+	}
+
+	unliked, err := s.repo.UnlikeComment(ctx, reqUri.UserId, commentId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	if unliked {
+		s.retract(ctx, notification.Event{
+			Type:      notification.TYPE_COMMENT_LIKE,
+			ActorID:   reqUri.UserId,
+			CommentID: &commentId,
+		})
+	}
+
+	return s.getCommentLikeState(ctx, commentId, false)
+}
+
+func (s postService) getCommentLikeState(ctx context.Context, commentId uuid.UUID, isLiked bool) (LikeResponse, error) {
+	totalLikes, err := s.repo.GetCommentLikeCount(ctx, commentId)
+	if err != nil {
+		return LikeResponse{}, err
+	}
+
+	return LikeResponse{
+		IsLiked:    isLiked,
+		TotalLikes: totalLikes,
+	}, nil
+}
+
+func toReactionsResponse(counts []ReactionCountQueryRes, reaction *string) ReactionsResponse {
+	res := ReactionsResponse{
+		Counts:   make(map[string]int, len(REACTIONS)),
+		Reaction: reaction,
+	}
+
+	for _, reaction := range REACTIONS {
+		res.Counts[reaction] = 0
+	}
+
+	for _, count := range counts {
+		res.Counts[count.Reaction] = count.Total
+		res.Total += count.Total
+	}
+
+	return res
+}
+
+func (s postService) ReactPost(ctx context.Context, reqUri PostAndUserUriRequest, reqBody ReactRequest) (ReactionsResponse, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	created, err := s.repo.ReactPost(ctx, reqUri.UserId, postId, reqBody.Reaction)
+	if err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	// Changing the reaction isn't worth another notification
+	if created {
+		s.publishPostCounts(ctx, postId)
+		s.notify(ctx, notification.Event{
+			Type:    notification.TYPE_POST_LIKE,
+			ActorID: reqUri.UserId,
+			PostID:  &postId,
+		})
+	}
+
+	return s.GetPostReactions(ctx, reqUri)
 }
 
-func (s postService) LikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) error {
+func (s postService) GetPostReactions(ctx context.Context, reqUri PostAndUserUriRequest) (ReactionsResponse, error) {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	counts, err := s.repo.GetPostReactionCounts(ctx, postId)
+	if err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	var reaction *string
+	postReaction, err := s.repo.GetPostReaction(ctx, reqUri.UserId, postId)
+	if err == nil {
+		reaction = &postReaction.Reaction
+	} else if err != gorm.ErrRecordNotFound {
+		return ReactionsResponse{}, err
+	}
+
+	return toReactionsResponse(counts, reaction), nil
+}
+
+func (s postService) ReactComment(ctx context.Context, reqUri CommentAndUserUriRequest, reqBody ReactRequest) (ReactionsResponse, error) {
 	commentId, _ := uuid.Parse(reqUri.CommentId)
-	return s.repo.LikeComment(ctx, reqUri.UserId, commentId)
+
+	comment, err := s.repo.GetComment(ctx, commentId)
+	if err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	if comment.DeletedAt.Valid {
+		return ReactionsResponse{}, gorm.ErrRecordNotFound
+	}
+
+	created, err := s.repo.ReactComment(ctx, reqUri.UserId, commentId, reqBody.Reaction)
+	if err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	if created {
+		s.notify(ctx, notification.Event{
+			Type:      notification.TYPE_COMMENT_LIKE,
+			ActorID:   reqUri.UserId,
+			CommentID: &commentId,
+		})
+	}
+
+	return s.GetCommentReactions(ctx, reqUri)
 }
 
-func (s postService) UnlikeComment(ctx context.Context, reqUri CommentAndUserUriRequest) error {
+func (s postService) GetCommentReactions(ctx context.Context, reqUri CommentAndUserUriRequest) (ReactionsResponse, error) {
 	commentId, _ := uuid.Parse(reqUri.CommentId)
-	return s.repo.UnlikeComment(ctx, reqUri.UserId, commentId)
+
+	counts, err := s.repo.GetCommentReactionCounts(ctx, commentId)
+	if err != nil {
+		return ReactionsResponse{}, err
+	}
+
+	var reaction *string
+	commentReaction, err := s.repo.GetCommentReaction(ctx, reqUri.UserId, commentId)
+	if err == nil {
+		reaction = &commentReaction.Reaction
+	} else if err != gorm.ErrRecordNotFound {
+		return ReactionsResponse{}, err
+	}
+
+	return toReactionsResponse(counts, reaction), nil
+}
+
+func (s postService) AuthorizeTopic(ctx context.Context, userId, topic string) error {
+	rest := strings.TrimPrefix(topic, realtime.POST_TOPIC_PREFIX)
+	postId, err := uuid.Parse(strings.TrimSuffix(rest, ":comments"))
+	if err != nil {
+		return fmt.Errorf("topic %v not allowed", topic)
+	}
+
+	// Drafts, scheduled, archived and deleted posts are not found
+	if _, err := s.repo.GetPost(ctx, postId); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("topic %v not allowed", topic)
+		}
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,115 @@
+package preference
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(db *gorm.DB) Handler {
+	return Handler{
+		Service: NewService(NewRepository(db)),
+	}
+}
+
+func (h Handler) GetPreferences(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's notification preferences.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's notification preferences.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	preferences, err := h.Service.GetPreferences(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's notification preferences.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's notification preferences fetched successfully.",
+		Data:    preferences,
+	})
+}
+
+func (h Handler) UpdatePreferences(ctx *gin.Context) {
+	var (
+		reqUri  internal.UserIdUriRequest
+		reqBody UpdatePreferencesRequest
+	)
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to update user's notification preferences.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to update user's notification preferences.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	preferences, err := h.Service.UpdatePreferences(ctx.Request.Context(), reqUri, reqBody)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to update user's notification preferences.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's notification preferences updated successfully.",
+		Data:    preferences,
+	})
+}
@@ -0,0 +1,70 @@
+package preference
+
+import (
+	"context"
+
+	"github.com/rrab-0/its-gram/internal"
+)
+
+// Preference types, each covers one or more notification types.
+const (
+	TYPE_LIKES    = "likes"
+	TYPE_COMMENTS = "comments"
+	TYPE_REPLIES  = "replies"
+	TYPE_FOLLOWS  = "follows"
+	TYPE_MENTIONS = "mentions"
+)
+
+const (
+	CHANNEL_IN_APP = "in_app"
+	CHANNEL_PUSH   = "push"
+	CHANNEL_EMAIL  = "email"
+)
+
+var TYPES = []string{TYPE_LIKES, TYPE_COMMENTS, TYPE_REPLIES, TYPE_FOLLOWS, TYPE_MENTIONS}
+
+// Profile applied to new users, also used for types a user has never changed.
+func Defaults(userId string) []internal.NotificationPreference {
+	preferences := make([]internal.NotificationPreference, 0, len(TYPES))
+	for _, preferenceType := range TYPES {
+		preferences = append(preferences, internal.NotificationPreference{
+			UserID: userId,
+			Type:   preferenceType,
+			InApp:  true,
+			Push:   true,
+			// Likes would flood the inbox
+			Email: preferenceType != TYPE_LIKES,
+		})
+	}
+
+	return preferences
+}
+
+type UpdatePreferencesRequest struct {
+	Preferences []UpdatePreferenceRequest `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// Channels left empty are unchanged.
+type UpdatePreferenceRequest struct {
+	Type  string `json:"type" binding:"required,oneof=likes comments replies follows mentions"`
+	InApp *bool  `json:"in_app"`
+	Push  *bool  `json:"push"`
+	Email *bool  `json:"email"`
+}
+
+type Repository interface {
+	GetPreferences(ctx context.Context, userId string) ([]internal.NotificationPreference, error)
+	GetPreference(ctx context.Context, userId, preferenceType string) (internal.NotificationPreference, error)
+	SavePreferences(ctx context.Context, preferences []internal.NotificationPreference) error
+}
+
+type Service interface {
+	// Used by notification and push to check a single notification type
+	Allows(ctx context.Context, userId, notificationType, channel string) (bool, error)
+	AllowsInApp(ctx context.Context, userId, notificationType string) (bool, error)
+	AllowsPush(ctx context.Context, userId, notificationType string) (bool, error)
+	AllowsEmail(ctx context.Context, userId, notificationType string) (bool, error)
+
+	GetPreferences(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody UpdatePreferencesRequest) ([]internal.NotificationPreference, error)
+}
@@ -0,0 +1,57 @@
+package preference
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+func (r gormRepository) GetPreferences(ctx context.Context, userId string) ([]internal.NotificationPreference, error) {
+	var preferences []internal.NotificationPreference
+
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userId).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (r gormRepository) GetPreference(ctx context.Context, userId, preferenceType string) (internal.NotificationPreference, error) {
+	var preference internal.NotificationPreference
+
+	err := r.db.WithContext(ctx).Where("user_id = ? AND type = ?", userId, preferenceType).First(&preference).Error
+	if err != nil {
+		return internal.NotificationPreference{}, err
+	}
+
+	return preference, nil
+}
+
+func (r gormRepository) SavePreferences(ctx context.Context, preferences []internal.NotificationPreference) error {
+	return r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"in_app":     gorm.Expr("excluded.in_app"),
+				"push":       gorm.Expr("excluded.push"),
+				"email":      gorm.Expr("excluded.email"),
+				"updated_at": time.Now(),
+			}),
+		}).
+		Create(&preferences).
+		Error
+}
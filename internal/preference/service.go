@@ -0,0 +1,137 @@
+package preference
+
+import (
+	"context"
+
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/notification"
+	"gorm.io/gorm"
+)
+
+type preferenceService struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return preferenceService{
+		repo: repo,
+	}
+}
+
+// Preference type that controls a notification type, empty if there's none.
+func typeOf(notificationType string) string {
+	switch notificationType {
+	case notification.TYPE_POST_LIKE, notification.TYPE_COMMENT_LIKE:
+		return TYPE_LIKES
+	case notification.TYPE_COMMENT:
+		return TYPE_COMMENTS
+	case notification.TYPE_REPLY:
+		return TYPE_REPLIES
+	case notification.TYPE_FOLLOW:
+		return TYPE_FOLLOWS
+	}
+
+	return ""
+}
+
+func defaultOf(userId, preferenceType string) internal.NotificationPreference {
+	for _, preference := range Defaults(userId) {
+		if preference.Type == preferenceType {
+			return preference
+		}
+	}
+
+	return internal.NotificationPreference{}
+}
+
+func (s preferenceService) Allows(ctx context.Context, userId, notificationType, channel string) (bool, error) {
+	preferenceType := typeOf(notificationType)
+	if preferenceType == "" {
+		return true, nil
+	}
+
+	preference, err := s.repo.GetPreference(ctx, userId, preferenceType)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return false, err
+		}
+		// Users registered before preferences existed
+		preference = defaultOf(userId, preferenceType)
+	}
+
+	switch channel {
+	case CHANNEL_IN_APP:
+		return preference.InApp, nil
+	case CHANNEL_PUSH:
+		return preference.Push, nil
+	case CHANNEL_EMAIL:
+		return preference.Email, nil
+	}
+
+	return false, nil
+}
+
+func (s preferenceService) AllowsInApp(ctx context.Context, userId, notificationType string) (bool, error) {
+	return s.Allows(ctx, userId, notificationType, CHANNEL_IN_APP)
+}
+
+func (s preferenceService) AllowsPush(ctx context.Context, userId, notificationType string) (bool, error) {
+	return s.Allows(ctx, userId, notificationType, CHANNEL_PUSH)
+}
+
+func (s preferenceService) AllowsEmail(ctx context.Context, userId, notificationType string) (bool, error) {
+	return s.Allows(ctx, userId, notificationType, CHANNEL_EMAIL)
+}
+
+// Stored preferences with defaults filled in for missing types, in TYPES order.
+func (s preferenceService) GetPreferences(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.NotificationPreference, error) {
+	stored, err := s.repo.GetPreferences(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	storedByType := make(map[string]internal.NotificationPreference, len(stored))
+	for _, preference := range stored {
+		storedByType[preference.Type] = preference
+	}
+
+	preferences := Defaults(reqUri.UserId)
+	for i, preference := range preferences {
+		if storedPreference, ok := storedByType[preference.Type]; ok {
+			preferences[i] = storedPreference
+		}
+	}
+
+	return preferences, nil
+}
+
+func (s preferenceService) UpdatePreferences(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody UpdatePreferencesRequest) ([]internal.NotificationPreference, error) {
+	preferences, err := s.GetPreferences(ctx, reqUri)
+	if err != nil {
+		return nil, err
+	}
+
+	indexByType := make(map[string]int, len(preferences))
+	for i, preference := range preferences {
+		indexByType[preference.Type] = i
+	}
+
+	for _, update := range reqBody.Preferences {
+		preference := &preferences[indexByType[update.Type]]
+		if update.InApp != nil {
+			preference.InApp = *update.InApp
+		}
+		if update.Push != nil {
+			preference.Push = *update.Push
+		}
+		if update.Email != nil {
+			preference.Email = *update.Email
+		}
+	}
+
+	if err := s.repo.SavePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	return s.GetPreferences(ctx, reqUri)
+}
@@ -0,0 +1,92 @@
+package push
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	firebase "firebase.google.com/go"
+	"firebase.google.com/go/messaging"
+)
+
+type fcmProvider struct {
+	client *messaging.Client
+}
+
+func NewFCMProvider(app *firebase.App) (Provider, error) {
+	client, err := app.Messaging(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return fcmProvider{
+		client: client,
+	}, nil
+}
+
+// Sends one message per token, the batch API of this SDK version is no longer served by FCM.
+func (p fcmProvider) Send(ctx context.Context, tokens []string, msg Message) ([]string, error) {
+	var (
+		invalidTokens []string
+		lastErr       error
+	)
+
+	for _, token := range tokens {
+		_, err := p.client.Send(ctx, &messaging.Message{
+			Token: token,
+			Notification: &messaging.Notification{
+				Title: msg.Title,
+				Body:  msg.Body,
+			},
+			Data: msg.Data,
+		})
+		if err == nil {
+			continue
+		}
+
+		if messaging.IsRegistrationTokenNotRegistered(err) {
+			invalidTokens = append(invalidTokens, token)
+			continue
+		}
+
+		lastErr = err
+	}
+
+	return invalidTokens, lastErr
+}
+
+// Doesn't send anything, logs and keeps messages instead.
+// For local development and tests.
+type FakeProvider struct {
+	mu   sync.Mutex
+	sent []FakeSentMessage
+}
+
+type FakeSentMessage struct {
+	Tokens  []string
+	Message Message
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Send(ctx context.Context, tokens []string, msg Message) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	log.Printf("PUSH: %q %q to %d device(s)", msg.Title, msg.Body, len(tokens))
+	p.sent = append(p.sent, FakeSentMessage{
+		Tokens:  tokens,
+		Message: msg,
+	})
+
+	return nil, nil
+}
+
+func (p *FakeProvider) Sent() []FakeSentMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]FakeSentMessage(nil), p.sent...)
+}
@@ -0,0 +1,60 @@
+package push
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+const (
+	PROVIDER_FCM  = "FCM"
+	PROVIDER_FAKE = "FAKE"
+)
+
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Delivers messages to devices, returns the tokens that aren't valid anymore
+// (app uninstalled, token expired) so they can be removed.
+type Provider interface {
+	Send(ctx context.Context, tokens []string, msg Message) (invalidTokens []string, err error)
+}
+
+// Decides whether a user wants pushes of a notification type, implemented by preference.Service.
+type PreferenceChecker interface {
+	AllowsPush(ctx context.Context, userId, notificationType string) (bool, error)
+}
+
+// Used by other packages to push to every device of a user.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, userId, notificationType string, msg Message) error
+}
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Platform string `json:"platform" binding:"required,oneof=android ios web"`
+}
+
+type DeviceUriRequest struct {
+	UserId   string `uri:"id" binding:"required"`
+	DeviceId string `uri:"deviceId" binding:"required,uuid"`
+}
+
+type Repository interface {
+	RegisterDevice(ctx context.Context, device internal.DeviceToken) (internal.DeviceToken, error)
+	GetDevices(ctx context.Context, userId string) ([]internal.DeviceToken, error)
+	DeleteDevice(ctx context.Context, userId string, deviceId uuid.UUID) error
+	DeleteTokens(ctx context.Context, tokens []string) error
+}
+
+type Service interface {
+	Dispatcher
+
+	RegisterDevice(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody RegisterDeviceRequest) (internal.DeviceToken, error)
+	GetDevices(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.DeviceToken, error)
+	DeleteDevice(ctx context.Context, reqUri DeviceUriRequest) error
+}
@@ -0,0 +1,76 @@
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+// A token moves to the latest user that registered it (e.g. logged out then in as someone else).
+func (r gormRepository) RegisterDevice(ctx context.Context, device internal.DeviceToken) (internal.DeviceToken, error) {
+	err := r.db.
+		WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "token"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"user_id":    device.UserID,
+					"platform":   device.Platform,
+					"updated_at": time.Now(),
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(&device).
+		Error
+	if err != nil {
+		return internal.DeviceToken{}, err
+	}
+
+	return device, nil
+}
+
+func (r gormRepository) GetDevices(ctx context.Context, userId string) ([]internal.DeviceToken, error) {
+	var devices []internal.DeviceToken
+
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("updated_at DESC").Find(&devices).Error; err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (r gormRepository) DeleteDevice(ctx context.Context, userId string, deviceId uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", deviceId, userId).Delete(&internal.DeviceToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) DeleteTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Where("token IN ?", tokens).Delete(&internal.DeviceToken{}).Error
+}
@@ -0,0 +1,83 @@
+package push
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+type pushService struct {
+	repo        Repository
+	provider    Provider
+	preferences PreferenceChecker
+}
+
+func NewService(repo Repository, provider Provider, preferences PreferenceChecker) Service {
+	return pushService{
+		repo:        repo,
+		provider:    provider,
+		preferences: preferences,
+	}
+}
+
+func (s pushService) Dispatch(ctx context.Context, userId, notificationType string, msg Message) error {
+	allowed, err := s.preferences.AllowsPush(ctx, userId, notificationType)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return nil
+	}
+
+	devices, err := s.repo.GetDevices(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		tokens = append(tokens, device.Token)
+	}
+
+	invalidTokens, sendErr := s.provider.Send(ctx, tokens, msg)
+
+	// Clean up even if some sends failed
+	if err := s.repo.DeleteTokens(ctx, invalidTokens); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+func (s pushService) RegisterDevice(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody RegisterDeviceRequest) (internal.DeviceToken, error) {
+	device, err := s.repo.RegisterDevice(ctx, internal.DeviceToken{
+		UserID:   reqUri.UserId,
+		Token:    reqBody.Token,
+		Platform: reqBody.Platform,
+	})
+	if err != nil {
+		return internal.DeviceToken{}, err
+	}
+
+	return device, nil
+}
+
+func (s pushService) GetDevices(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.DeviceToken, error) {
+	devices, err := s.repo.GetDevices(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (s pushService) DeleteDevice(ctx context.Context, reqUri DeviceUriRequest) error {
+	deviceId, _ := uuid.Parse(reqUri.DeviceId)
+	return s.repo.DeleteDevice(ctx, reqUri.UserId, deviceId)
+}
@@ -0,0 +1,42 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryBroker struct {
+	mu       sync.RWMutex
+	delivers []func(Event)
+}
+
+func NewMemoryBroker() Broker {
+	return &memoryBroker{}
+}
+
+func (b *memoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, deliver := range b.delivers {
+		deliver(event)
+	}
+
+	return nil
+}
+
+func (b *memoryBroker) Listen(ctx context.Context, deliver func(Event)) error {
+	b.mu.Lock()
+	b.delivers = append(b.delivers, deliver)
+	idx := len(b.delivers) - 1
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		b.delivers[idx] = func(Event) {}
+		b.mu.Unlock()
+	}()
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+// Keeps idle connections from being closed by proxies.
+const HEARTBEAT_INTERVAL = 15 * time.Second
+
+type Handler struct {
+	hub         *Hub
+	authorizers map[string]TopicAuthorizer // By topic prefix
+}
+
+func NewHandler(hub *Hub, authorizers map[string]TopicAuthorizer) Handler {
+	return Handler{
+		hub:         hub,
+		authorizers: authorizers,
+	}
+}
+
+// Topics of a prefix without a TopicAuthorizer are not allowed.
+func authorizeTopic(ctx context.Context, authorizers map[string]TopicAuthorizer, userId, topic string) error {
+	for prefix, authorizer := range authorizers {
+		if strings.HasPrefix(topic, prefix) {
+			return authorizer.AuthorizeTopic(ctx, userId, topic)
+		}
+	}
+
+	return fmt.Errorf("topic %v not allowed", topic)
+}
+
+// Server-Sent Events stream of the user's notifications and feed hints,
+// plus count updates of posts listed in the "posts" query.
+func (h Handler) StreamEvents(ctx *gin.Context) {
+	var (
+		reqUri   internal.UserIdUriRequest
+		reqQuery StreamEventsQueryRequest
+	)
+
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to stream events.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to stream events.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	topics := []string{UserTopic(reqUri.UserId)}
+	if reqQuery.Posts != "" {
+		for _, postId := range strings.Split(reqQuery.Posts, ",") {
+			if _, err := uuid.Parse(postId); err != nil {
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+					Message: "Invalid request.",
+					Error:   postId + " is not a valid uuid",
+				})
+				return
+			}
+
+			topic := PostTopic(postId)
+			if err := authorizeTopic(ctx.Request.Context(), h.authorizers, reqUri.UserId, topic); err != nil {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+					Message: "Failed to stream events.",
+					Error:   err.Error(),
+				})
+				return
+			}
+			topics = append(topics, topic)
+		}
+	}
+
+	sub := h.hub.Subscribe(topics...)
+	defer h.hub.Unsubscribe(sub)
+
+	heartbeat := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	ctx.Writer.Header().Set("Content-Type", "text/event-stream")
+	ctx.Writer.Header().Set("Cache-Control", "no-cache")
+	ctx.Writer.Header().Set("Connection", "keep-alive")
+	ctx.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	// Let the client know the stream is open without waiting for the first event
+	ctx.Writer.WriteHeader(http.StatusOK)
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-sub.Overflow():
+			return false
+		case event := <-sub.Events():
+			ctx.SSEvent(event.Type, event)
+			return true
+		case now := <-heartbeat.C:
+			ctx.SSEvent(TYPE_HEARTBEAT, now)
+			return true
+		}
+	})
+}
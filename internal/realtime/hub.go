@@ -0,0 +1,109 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// How many undelivered events a subscriber can have before it's considered too slow.
+const SUBSCRIBER_BUFFER_SIZE = 64
+
+// A connected client, receives events of the topics it's subscribed to.
+type Subscriber struct {
+	events       chan Event
+	overflow     chan struct{}
+	overflowOnce sync.Once
+}
+
+func (s *Subscriber) Events() <-chan Event {
+	return s.events
+}
+
+// Closed when the subscriber couldn't keep up and events were dropped,
+// the connection should be closed so the client can reconnect and refetch.
+func (s *Subscriber) Overflow() <-chan struct{} {
+	return s.overflow
+}
+
+// Fans out events from the Broker to subscribers of this instance.
+type Hub struct {
+	broker Broker
+
+	mu     sync.RWMutex
+	topics map[string]map[*Subscriber]struct{}
+}
+
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker: broker,
+		topics: make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+func (h *Hub) Start(ctx context.Context) error {
+	return h.broker.Listen(ctx, h.deliver)
+}
+
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	return h.broker.Publish(ctx, event)
+}
+
+func (h *Hub) Subscribe(topics ...string) *Subscriber {
+	sub := &Subscriber{
+		events:   make(chan Event, SUBSCRIBER_BUFFER_SIZE),
+		overflow: make(chan struct{}),
+	}
+
+	h.AddTopics(sub, topics...)
+	return sub
+}
+
+func (h *Hub) AddTopics(sub *Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Subscriber]struct{})
+		}
+		h.topics[topic][sub] = struct{}{}
+	}
+}
+
+func (h *Hub) RemoveTopics(sub *Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(h.topics[topic], sub)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic, subs := range h.topics {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// Never blocks, a subscriber with a full buffer gets its overflow closed instead.
+func (h *Hub) deliver(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.topics[event.Topic] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.overflowOnce.Do(func() { close(sub.overflow) })
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package realtime
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	TYPE_NOTIFICATION = "notification"
+	TYPE_FEED_ITEM    = "feed_item"
+	TYPE_POST_COUNTS  = "post_counts"
+	TYPE_COMMENT      = "comment"
+	TYPE_MESSAGE      = "message"
+	TYPE_MESSAGE_GONE = "message_deleted"
+	TYPE_READ_RECEIPT = "read_receipt"
+	TYPE_TYPING       = "typing"
+	TYPE_HEARTBEAT    = "heartbeat"
+)
+
+type Event struct {
+	Topic string `json:"topic"`
+	Type  string `json:"type"`
+	Data  any    `json:"data"`
+}
+
+// Everything sent to a single user (notifications, feed hints).
+func UserTopic(userId string) string {
+	return "user:" + userId
+}
+
+const POST_TOPIC_PREFIX = "post:"
+
+// Like and comment count updates of a post, only while other users can see it.
+func PostTopic(postId string) string {
+	return POST_TOPIC_PREFIX + postId
+}
+
+// New comments and replies of a post, same as PostTopic.
+func PostCommentsTopic(postId string) string {
+	return POST_TOPIC_PREFIX + postId + ":comments"
+}
+
+const CONVERSATION_TOPIC_PREFIX = "conversation:"
+
+// Typing indicators of a conversation, only for its participants.
+func ConversationTopic(conversationId string) string {
+	return CONVERSATION_TOPIC_PREFIX + conversationId
+}
+
+// Used by other packages to send events to connected clients.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Carries events between server instances, every event published
+// on any instance has to be delivered to every instance's Hub.
+// The in-process broker from NewMemoryBroker is enough for a single instance,
+// multi-instance deployments need one backed by e.g. Redis or PostgreSQL LISTEN/NOTIFY.
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	// Starts delivering events to deliver, stops when ctx is done.
+	Listen(ctx context.Context, deliver func(Event)) error
+}
+
+// Decides whether a user may subscribe to a topic, registered per topic prefix
+// for topics that only some users can see (e.g. participants of a conversation).
+type TopicAuthorizer interface {
+	AuthorizeTopic(ctx context.Context, userId, topic string) error
+}
+
+type FeedItemEventData struct {
+	PostID    string `json:"post_id"`
+	CreatedBy string `json:"created_by"`
+}
+
+type PostCountsEventData struct {
+	PostID        string `json:"post_id"`
+	TotalLikes    int    `json:"total_likes"`
+	TotalComments int    `json:"total_comments"`
+}
+
+type StreamEventsQueryRequest struct {
+	// Comma separated post ids to receive count updates of
+	Posts string `form:"posts"`
+}
+
+type MessageDeletedEventData struct {
+	ConversationID string `json:"conversation_id"`
+	MessageID      string `json:"message_id"`
+}
+
+type ReadReceiptEventData struct {
+	ConversationID string    `json:"conversation_id"`
+	UserID         string    `json:"user_id"`
+	LastReadAt     time.Time `json:"last_read_at"`
+}
+
+type TypingEventData struct {
+	ConversationID string `json:"conversation_id"`
+	UserID         string `json:"user_id"`
+	IsTyping       bool   `json:"is_typing"`
+}
@@ -0,0 +1,291 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rrab-0/its-gram/internal"
+	"golang.org/x/time/rate"
+)
+
+const (
+	WS_WRITE_WAIT     = 10 * time.Second
+	WS_PONG_WAIT      = 60 * time.Second
+	WS_PING_INTERVAL  = (WS_PONG_WAIT * 9) / 10
+	WS_MAX_MESSAGE    = 4096
+	WS_OUTBOX_SIZE    = 16
+	WS_MAX_TOPICS     = 50
+	WS_MESSAGES_RATE  = 5 // Per second
+	WS_MESSAGES_BURST = 10
+)
+
+// Client -> server message types
+const (
+	WS_SUBSCRIBE    = "subscribe"
+	WS_UNSUBSCRIBE  = "unsubscribe"
+	WS_PING         = "ping"
+	WS_TYPING_START = "typing_start" // Topic is a subscribed conversation:<id>
+	WS_TYPING_STOP  = "typing_stop"
+)
+
+// Server -> client message types, events from the hub are sent as is
+const (
+	WS_SUBSCRIBED   = "subscribed"
+	WS_UNSUBSCRIBED = "unsubscribed"
+	WS_PONG         = "pong"
+	WS_ERROR        = "error"
+)
+
+type ClientMessage struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic"`
+}
+
+type ServerMessage struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// CORS already allows any origin, the token is what protects the connection.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+type wsConnection struct {
+	ctx         context.Context
+	hub         *Hub
+	authorizers map[string]TopicAuthorizer
+	conn        *websocket.Conn
+	sub         *Subscriber
+	userId      string
+	limiter     *rate.Limiter
+
+	outbox    chan ServerMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Only touched by the read loop
+	topics map[string]bool
+}
+
+// WebSocket connection that's always subscribed to the user's own topic,
+// other topics (e.g. a post's comments) are (un)subscribed with ClientMessage.
+func (h Handler) ServeWebSocket(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to open websocket.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to open websocket.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	// Upgrade already responds to the client on failure
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		log.Printf("ERROR: Failed to upgrade websocket of user %v: %v", reqUri.UserId, err.Error())
+		return
+	}
+
+	userTopic := UserTopic(reqUri.UserId)
+	c := &wsConnection{
+		ctx:         ctx.Request.Context(),
+		hub:         h.hub,
+		authorizers: h.authorizers,
+		conn:        conn,
+		sub:         h.hub.Subscribe(userTopic),
+		userId:      reqUri.UserId,
+		limiter:     rate.NewLimiter(WS_MESSAGES_RATE, WS_MESSAGES_BURST),
+		outbox:      make(chan ServerMessage, WS_OUTBOX_SIZE),
+		done:        make(chan struct{}),
+		topics:      map[string]bool{userTopic: true},
+	}
+
+	go c.writeLoop()
+	c.readLoop()
+}
+
+func (c *wsConnection) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.hub.Unsubscribe(c.sub)
+		c.conn.Close()
+	})
+}
+
+// Replies are dropped along with the connection if the client stopped reading.
+func (c *wsConnection) send(msg ServerMessage) {
+	select {
+	case c.outbox <- msg:
+	default:
+		c.close()
+	}
+}
+
+func (c *wsConnection) readLoop() {
+	defer c.close()
+
+	c.conn.SetReadLimit(WS_MAX_MESSAGE)
+	c.conn.SetReadDeadline(time.Now().Add(WS_PONG_WAIT))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(WS_PONG_WAIT))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if !c.limiter.Allow() {
+			c.send(ServerMessage{Type: WS_ERROR, Error: "rate limit exceeded"})
+			continue
+		}
+
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.send(ServerMessage{Type: WS_ERROR, Error: "invalid message"})
+			continue
+		}
+
+		switch msg.Type {
+		case WS_PING:
+			c.send(ServerMessage{Type: WS_PONG})
+
+		case WS_SUBSCRIBE:
+			if err := c.authorizeTopic(msg.Topic); err != nil {
+				c.send(ServerMessage{Type: WS_ERROR, Topic: msg.Topic, Error: err.Error()})
+				continue
+			}
+
+			if !c.topics[msg.Topic] && len(c.topics) >= WS_MAX_TOPICS {
+				c.send(ServerMessage{Type: WS_ERROR, Topic: msg.Topic, Error: "too many topics"})
+				continue
+			}
+
+			c.topics[msg.Topic] = true
+			c.hub.AddTopics(c.sub, msg.Topic)
+			c.send(ServerMessage{Type: WS_SUBSCRIBED, Topic: msg.Topic})
+
+		case WS_UNSUBSCRIBE:
+			// The user's own topic stays for the whole connection
+			if msg.Topic == UserTopic(c.userId) || !c.topics[msg.Topic] {
+				c.send(ServerMessage{Type: WS_ERROR, Topic: msg.Topic, Error: "not subscribed"})
+				continue
+			}
+
+			delete(c.topics, msg.Topic)
+			c.hub.RemoveTopics(c.sub, msg.Topic)
+			c.send(ServerMessage{Type: WS_UNSUBSCRIBED, Topic: msg.Topic})
+
+		case WS_TYPING_START, WS_TYPING_STOP:
+			// Subscribing already checked that the user is a participant
+			conversationId, ok := strings.CutPrefix(msg.Topic, CONVERSATION_TOPIC_PREFIX)
+			if !ok || !c.topics[msg.Topic] {
+				c.send(ServerMessage{Type: WS_ERROR, Topic: msg.Topic, Error: "not subscribed to conversation"})
+				continue
+			}
+
+			// Not stored anywhere, clients should treat a missing stop as stopped after a few seconds
+			err := c.hub.Publish(c.ctx, Event{
+				Topic: msg.Topic,
+				Type:  TYPE_TYPING,
+				Data: TypingEventData{
+					ConversationID: conversationId,
+					UserID:         c.userId,
+					IsTyping:       msg.Type == WS_TYPING_START,
+				},
+			})
+			if err != nil {
+				log.Printf("ERROR: Failed to publish typing of user %v: %v", c.userId, err.Error())
+			}
+
+		default:
+			c.send(ServerMessage{Type: WS_ERROR, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
+
+func (c *wsConnection) writeLoop() {
+	ping := time.NewTicker(WS_PING_INTERVAL)
+	defer func() {
+		ping.Stop()
+		c.close()
+	}()
+
+	for {
+		var (
+			payload any
+			err     error
+		)
+
+		select {
+		case <-c.done:
+			return
+
+		case <-c.sub.Overflow():
+			c.conn.SetWriteDeadline(time.Now().Add(WS_WRITE_WAIT))
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "slow consumer"))
+			return
+
+		case <-ping.C:
+			err = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(WS_WRITE_WAIT))
+			if err != nil {
+				return
+			}
+			continue
+
+		case event := <-c.sub.Events():
+			payload = event
+
+		case msg := <-c.outbox:
+			payload = msg
+		}
+
+		c.conn.SetWriteDeadline(time.Now().Add(WS_WRITE_WAIT))
+		if err = c.conn.WriteJSON(payload); err != nil {
+			return
+		}
+	}
+}
+
+// Allowed topics:
+// - user:<own id>
+// - anything accepted by the TopicAuthorizer of its prefix (e.g. post:<id>, conversation:<id>)
+func (c *wsConnection) authorizeTopic(topic string) error {
+	if topic == UserTopic(c.userId) {
+		return nil
+	}
+
+	return authorizeTopic(c.ctx, c.authorizers, c.userId, topic)
+}
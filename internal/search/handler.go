@@ -0,0 +1,226 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/post"
+	"github.com/rrab-0/its-gram/internal/user"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(db *gorm.DB, userService user.Service, postService post.Service) Handler {
+	return Handler{
+		Service: NewService(NewRepository(db), userService, postService),
+	}
+}
+
+const (
+	MAXIMUM_LIMIT = 20
+	MINIMUM_LIMIT = 5
+)
+
+func (h Handler) Search(ctx *gin.Context) {
+	var reqQuery SearchRequest
+	if err := ctx.Bind(&reqQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	if reqQuery.Limit < MINIMUM_LIMIT {
+		reqQuery.Limit = MINIMUM_LIMIT
+	} else if reqQuery.Limit > MAXIMUM_LIMIT {
+		reqQuery.Limit = MAXIMUM_LIMIT
+	}
+
+	results, err := h.Service.Search(ctx.Request.Context(), reqQuery)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to search, nothing found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to search.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "Searched successfully.",
+		Data:    results,
+	})
+}
+
+// Records the query of a successful search made by a signed in user,
+// put it in front of any search handler after an (optional) token validator.
+func (h Handler) RecordSearch(queryField, searchType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		userId, idExists := ctx.Get("user_id")
+		if !idExists || ctx.Writer.Status() != http.StatusOK {
+			return
+		}
+
+		id, ok := userId.(string)
+		if !ok || id == "" {
+			return
+		}
+
+		recordType := searchType
+		if recordType == "" {
+			recordType = ctx.Query("type")
+		}
+
+		if err := h.Service.RecordSearch(ctx.Request.Context(), id, ctx.Query(queryField), recordType); err != nil {
+			log.Printf("ERROR: Failed to record search of user %v: %v", id, err.Error())
+		}
+	}
+}
+
+func (h Handler) GetRecentSearches(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to fetch user's recent searches.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to fetch user's recent searches.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	searches, err := h.Service.GetRecentSearches(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to fetch user's recent searches.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's recent searches fetched successfully.",
+		Data:    searches,
+	})
+}
+
+func (h Handler) DeleteRecentSearch(ctx *gin.Context) {
+	var reqUri RecentSearchUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to delete recent search.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to delete recent search.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	err := h.Service.DeleteRecentSearch(ctx.Request.Context(), reqUri)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, internal.ErrorResponse{
+				Message: "Failed to delete recent search, recent search not found.",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to delete recent search.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: fmt.Sprintf("Recent search with id %v deleted successfully.", reqUri.SearchId),
+	})
+}
+
+func (h Handler) ClearRecentSearches(ctx *gin.Context) {
+	var reqUri internal.UserIdUriRequest
+	if err := ctx.ShouldBindUri(&reqUri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &internal.ErrorResponse{
+			Message: "Invalid request.",
+			Error:   internal.GenerateRequestValidatorError(err).Error(),
+		})
+		return
+	}
+
+	userId, idExists := ctx.Get("user_id")
+	if !idExists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, &internal.ErrorResponse{
+			Message: "Failed to clear recent searches.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	if reqUri.UserId != userId {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, &internal.ErrorResponse{
+			Message: "Failed to clear recent searches.",
+			Error:   "invalid token",
+		})
+		return
+	}
+
+	err := h.Service.ClearRecentSearches(ctx.Request.Context(), reqUri)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, internal.ErrorResponse{
+			Message: "Failed to clear recent searches.",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, internal.SuccessResponse{
+		Message: "User's recent searches cleared successfully.",
+	})
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+// Searching the same query again moves it to the top instead of duplicating it,
+// then anything past MAXIMUM_RECENT_SEARCHES is removed.
+func (r gormRepository) RecordSearch(ctx context.Context, userId, query, searchType string) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "query"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"updated_at": time.Now(),
+				"type":       searchType,
+			}),
+		}).
+		Create(&internal.RecentSearch{
+			UserID: userId,
+			Query:  query,
+			Type:   searchType,
+		}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	latestSearches := tx.
+		Model(&internal.RecentSearch{}).
+		Select("id").
+		Where("user_id = ?", userId).
+		Order("updated_at DESC").
+		Limit(MAXIMUM_RECENT_SEARCHES)
+
+	err = tx.
+		Where("user_id = ? AND id NOT IN (?)", userId, latestSearches).
+		Delete(&internal.RecentSearch{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r gormRepository) GetRecentSearches(ctx context.Context, userId string) ([]internal.RecentSearch, error) {
+	var searches []internal.RecentSearch
+
+	err := r.db.
+		WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("updated_at DESC").
+		Limit(MAXIMUM_RECENT_SEARCHES).
+		Find(&searches).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+func (r gormRepository) DeleteRecentSearch(ctx context.Context, userId string, searchId uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", searchId, userId).Delete(&internal.RecentSearch{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r gormRepository) ClearRecentSearches(ctx context.Context, userId string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&internal.RecentSearch{}).Error
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/post"
+)
+
+const (
+	TYPE_ALL     = "all"
+	TYPE_USER    = "user"
+	TYPE_POST    = "post"
+	TYPE_HASHTAG = "hashtag"
+)
+
+// Older searches are dropped once a user has more than this.
+const MAXIMUM_RECENT_SEARCHES = 20
+
+type SearchRequest struct {
+	Query string `form:"q" binding:"required,min=2"`
+	Type  string `form:"type" binding:"omitempty,oneof=user post hashtag"`
+	Limit int    `form:"limit"`
+}
+
+type SearchUserQueryRes struct {
+	Type string        `json:"type"`
+	User internal.User `json:"user"`
+}
+
+type SearchPostQueryRes struct {
+	Type string        `json:"type"`
+	Post internal.Post `json:"post"`
+}
+
+type SearchHashtagQueryRes struct {
+	Type    string               `json:"type"`
+	Hashtag post.HashtagQueryRes `json:"hashtag"`
+}
+
+type RecentSearchUriRequest struct {
+	UserId   string `uri:"id" binding:"required"`
+	SearchId string `uri:"searchId" binding:"required,uuid"`
+}
+
+type Repository interface {
+	RecordSearch(ctx context.Context, userId, query, searchType string) error
+	GetRecentSearches(ctx context.Context, userId string) ([]internal.RecentSearch, error)
+	DeleteRecentSearch(ctx context.Context, userId string, searchId uuid.UUID) error
+	ClearRecentSearches(ctx context.Context, userId string) error
+}
+
+type Service interface {
+	Search(ctx context.Context, reqQuery SearchRequest) ([]any, error)
+
+	RecordSearch(ctx context.Context, userId, query, searchType string) error
+	GetRecentSearches(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.RecentSearch, error)
+	DeleteRecentSearch(ctx context.Context, reqUri RecentSearchUriRequest) error
+	ClearRecentSearches(ctx context.Context, reqUri internal.UserIdUriRequest) error
+}
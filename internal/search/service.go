@@ -0,0 +1,141 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/post"
+	"github.com/rrab-0/its-gram/internal/user"
+	"gorm.io/gorm"
+)
+
+type searchService struct {
+	repo        Repository
+	userService user.Service
+	postService post.Service
+}
+
+func NewService(repo Repository, userService user.Service, postService post.Service) Service {
+	return searchService{
+		repo:        repo,
+		userService: userService,
+		postService: postService,
+	}
+}
+
+// Fans out to every requested type concurrently,
+// results are merged in users, posts, hashtags order.
+func (s searchService) Search(ctx context.Context, reqQuery SearchRequest) ([]any, error) {
+	var (
+		wg       sync.WaitGroup
+		users    []internal.User
+		posts    post.SearchPostsResponse
+		hashtags []post.HashtagQueryRes
+		errs     = make([]error, 3)
+	)
+
+	if reqQuery.Type == "" || reqQuery.Type == TYPE_USER {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			users, errs[0] = s.userService.SearchUser(ctx, user.UserSearchRequest{
+				Username: reqQuery.Query,
+				Page:     1,
+				Limit:    reqQuery.Limit,
+			})
+		}()
+	}
+
+	if reqQuery.Type == "" || reqQuery.Type == TYPE_POST {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			posts, errs[1] = s.postService.SearchPosts(ctx, post.SearchPostsQueryRequest{
+				Query: reqQuery.Query,
+				Limit: reqQuery.Limit,
+			})
+		}()
+	}
+
+	if reqQuery.Type == "" || reqQuery.Type == TYPE_HASHTAG {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hashtags, errs[2] = s.postService.SearchHashtags(ctx, post.SearchHashtagsQueryRequest{
+				Query: reqQuery.Query,
+				Limit: reqQuery.Limit,
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	// No results of one type shouldn't fail the whole search
+	for _, err := range errs {
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	results := []any{}
+	for _, foundUser := range users {
+		results = append(results, SearchUserQueryRes{
+			Type: TYPE_USER,
+			User: foundUser,
+		})
+	}
+
+	for _, foundPost := range posts.Posts {
+		results = append(results, SearchPostQueryRes{
+			Type: TYPE_POST,
+			Post: foundPost,
+		})
+	}
+
+	for _, hashtag := range hashtags {
+		results = append(results, SearchHashtagQueryRes{
+			Type:    TYPE_HASHTAG,
+			Hashtag: hashtag,
+		})
+	}
+
+	if len(results) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return results, nil
+}
+
+func (s searchService) RecordSearch(ctx context.Context, userId, query, searchType string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	if searchType == "" {
+		searchType = TYPE_ALL
+	}
+
+	return s.repo.RecordSearch(ctx, userId, query, searchType)
+}
+
+func (s searchService) GetRecentSearches(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.RecentSearch, error) {
+	searches, err := s.repo.GetRecentSearches(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+func (s searchService) DeleteRecentSearch(ctx context.Context, reqUri RecentSearchUriRequest) error {
+	searchId, _ := uuid.Parse(reqUri.SearchId)
+	return s.repo.DeleteRecentSearch(ctx, reqUri.UserId, searchId)
+}
+
+func (s searchService) ClearRecentSearches(ctx context.Context, reqUri internal.UserIdUriRequest) error {
+	return s.repo.ClearRecentSearches(ctx, reqUri.UserId)
+}
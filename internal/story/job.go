@@ -0,0 +1,26 @@
+package story
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrab-0/its-gram/internal"
+)
+
+// Periodically marks stories past their ExpiresAt as expired.
+// Reads already hide them, this keeps ExpiredAt accurate for the owner.
+type Job struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewJob(service Service, interval time.Duration) Job {
+	return Job{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j Job) Start(ctx context.Context) {
+	internal.RunEvery(ctx, j.interval, "expire stories", j.service.ExpireStories)
+}
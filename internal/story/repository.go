@@ -0,0 +1,404 @@
+package story
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return gormRepository{
+		db: db,
+	}
+}
+
+func (r gormRepository) CreateStory(ctx context.Context, story internal.Story) (internal.Story, error) {
+	if err := r.db.WithContext(ctx).Omit("CreatedBy").Create(&story).Error; err != nil {
+		return internal.Story{}, err
+	}
+
+	if err := r.db.WithContext(ctx).Preload("CreatedBy").First(&story, "id = ?", story.ID).Error; err != nil {
+		return internal.Story{}, err
+	}
+
+	return story, nil
+}
+
+func (r gormRepository) GetStory(ctx context.Context, storyId uuid.UUID) (internal.Story, error) {
+	var story internal.Story
+
+	if err := r.db.WithContext(ctx).Where("id = ?", storyId).First(&story).Error; err != nil {
+		return internal.Story{}, err
+	}
+
+	return story, nil
+}
+
+func (r gormRepository) DeleteStory(ctx context.Context, userId string, storyId uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// Highlights keep their other stories
+	if err := tx.Exec("DELETE FROM highlight_stories WHERE story_id = ?", storyId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	res := tx.Where("id = ? AND user_id = ?", storyId, userId).Delete(&internal.Story{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Where("story_id = ?", storyId).Delete(&internal.StoryView{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Oldest first, the order they're watched in.
+func (r gormRepository) GetActiveStories(ctx context.Context, userId string, now time.Time) ([]internal.Story, error) {
+	var stories []internal.Story
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Where("user_id = ? AND expired_at IS NULL AND expires_at > ?", userId, now).
+		Order("created_at").
+		Find(&stories).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stories, nil
+}
+
+// Followed users with active stories they haven't all seen yet, latest first.
+func (r gormRepository) GetTray(ctx context.Context, userId string, now time.Time, page, limit int) ([]TrayQueryRes, error) {
+	var (
+		user       internal.User
+		followings []internal.User
+		tray       []TrayQueryRes
+	)
+
+	user.ID = userId
+	if err := r.db.WithContext(ctx).Model(&user).Association("Followings").Find(&followings); err != nil {
+		return nil, err
+	}
+
+	if len(followings) == 0 {
+		return tray, nil
+	}
+
+	followingIds := make([]string, 0, len(followings))
+	for _, following := range followings {
+		followingIds = append(followingIds, following.ID)
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+	SELECT
+		stories.user_id,
+		MAX(stories.created_at) AS latest_at,
+		COUNT(*) AS total_stories,
+		COUNT(*) FILTER (WHERE story_views.viewer_id IS NULL) AS unseen_stories
+	FROM stories
+	LEFT JOIN story_views ON story_views.story_id = stories.id AND story_views.viewer_id = @userId
+	WHERE stories.user_id IN @followingIds
+	AND stories.expired_at IS NULL
+	AND stories.expires_at > @now
+	GROUP BY stories.user_id
+	HAVING COUNT(*) FILTER (WHERE story_views.viewer_id IS NULL) > 0
+	ORDER BY latest_at DESC, stories.user_id
+	OFFSET @offset
+	LIMIT @limit
+	`, map[string]interface{}{
+		"userId":       userId,
+		"followingIds": followingIds,
+		"now":          now,
+		"offset":       (page - 1) * limit,
+		"limit":        limit,
+	}).Scan(&tray).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tray, nil
+}
+
+func (r gormRepository) GetUsers(ctx context.Context, userIds []string) ([]internal.User, error) {
+	var users []internal.User
+
+	if len(userIds) == 0 {
+		return users, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r gormRepository) CreateView(ctx context.Context, view internal.StoryView) error {
+	return r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit("Viewer").
+		Create(&view).
+		Error
+}
+
+func (r gormRepository) GetSeenStoryIds(ctx context.Context, viewerId string, storyIds []uuid.UUID) ([]uuid.UUID, error) {
+	var seenStoryIds []uuid.UUID
+
+	if len(storyIds) == 0 {
+		return seenStoryIds, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.StoryView{}).
+		Where("viewer_id = ? AND story_id IN ?", viewerId, storyIds).
+		Pluck("story_id", &seenStoryIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return seenStoryIds, nil
+}
+
+func (r gormRepository) GetViewCounts(ctx context.Context, storyIds []uuid.UUID) ([]ViewCountQueryRes, error) {
+	var viewCounts []ViewCountQueryRes
+
+	if len(storyIds) == 0 {
+		return viewCounts, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.StoryView{}).
+		Select("story_id, COUNT(*) AS total_views").
+		Where("story_id IN ?", storyIds).
+		Group("story_id").
+		Scan(&viewCounts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return viewCounts, nil
+}
+
+func (r gormRepository) GetViews(ctx context.Context, storyId uuid.UUID, page, limit int) ([]internal.StoryView, error) {
+	var views []internal.StoryView
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Viewer").
+		Where("story_id = ?", storyId).
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&views).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (r gormRepository) ExpireStories(ctx context.Context, now time.Time) error {
+	return r.db.
+		WithContext(ctx).
+		Model(&internal.Story{}).
+		Where("expired_at IS NULL AND expires_at <= ?", now).
+		Update("expired_at", now).
+		Error
+}
+
+// Only the ones that belong to "user" and already expired, oldest first.
+func (r gormRepository) GetExpiredStories(ctx context.Context, userId string, storyIds []uuid.UUID) ([]internal.Story, error) {
+	var stories []internal.Story
+
+	err := r.db.
+		WithContext(ctx).
+		Where("user_id = ? AND expired_at IS NOT NULL AND id IN ?", userId, storyIds).
+		Order("created_at").
+		Find(&stories).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stories, nil
+}
+
+func orderHighlightStories(db *gorm.DB) *gorm.DB {
+	return db.Order("stories.created_at")
+}
+
+func (r gormRepository) GetHighlight(ctx context.Context, highlightId uuid.UUID) (internal.Highlight, error) {
+	var highlight internal.Highlight
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Stories", orderHighlightStories).
+		Where("id = ?", highlightId).
+		First(&highlight).
+		Error
+	if err != nil {
+		return internal.Highlight{}, err
+	}
+
+	return highlight, nil
+}
+
+func (r gormRepository) GetHighlights(ctx context.Context, userId string) ([]internal.Highlight, error) {
+	var highlights []internal.Highlight
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Stories", orderHighlightStories).
+		Where("user_id = ?", userId).
+		Order("position, created_at").
+		Find(&highlights).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return highlights, nil
+}
+
+// New highlights go after the existing ones.
+func (r gormRepository) CreateHighlight(ctx context.Context, highlight internal.Highlight) (internal.Highlight, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
+		Model(&internal.Highlight{}).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Where("user_id = ?", highlight.UserID).
+		Scan(&highlight.Position).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return internal.Highlight{}, err
+	}
+
+	// Only link the stories, they already exist
+	if err := tx.Omit("Stories.*").Create(&highlight).Error; err != nil {
+		tx.Rollback()
+		return internal.Highlight{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Highlight{}, err
+	}
+
+	return r.GetHighlight(ctx, highlight.ID)
+}
+
+func (r gormRepository) UpdateHighlight(ctx context.Context, highlight internal.Highlight, updateStories bool) (internal.Highlight, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
+		Model(&internal.Highlight{}).
+		Where("id = ?", highlight.ID).
+		Updates(map[string]interface{}{
+			"name":       highlight.Name,
+			"cover_link": highlight.CoverLink,
+		}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return internal.Highlight{}, err
+	}
+
+	if updateStories {
+		err = tx.Model(&internal.Highlight{ID: highlight.ID}).Association("Stories").Replace(highlight.Stories)
+		if err != nil {
+			tx.Rollback()
+			return internal.Highlight{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return internal.Highlight{}, err
+	}
+
+	return r.GetHighlight(ctx, highlight.ID)
+}
+
+func (r gormRepository) ReorderHighlights(ctx context.Context, userId string, highlightIds []uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	for position, highlightId := range highlightIds {
+		err := tx.
+			Model(&internal.Highlight{}).
+			Where("id = ? AND user_id = ?", highlightId, userId).
+			Update("position", position).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r gormRepository) DeleteHighlight(ctx context.Context, userId string, highlightId uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// Stories stay, only the links are removed
+	err := tx.Exec(`
+	DELETE FROM highlight_stories
+	WHERE highlight_id IN (SELECT id FROM highlights WHERE id = ? AND user_id = ?)
+	`, highlightId, userId).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	res := tx.Where("id = ? AND user_id = ?", highlightId, userId).Delete(&internal.Highlight{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
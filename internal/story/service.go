@@ -0,0 +1,318 @@
+package story
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotOwner              = errors.New("only the owner can see who viewed a story")
+	ErrStoriesNotExpired     = errors.New("highlights can only have your own expired stories")
+	ErrInvalidHighlightOrder = errors.New("highlight_ids must have every highlight exactly once")
+)
+
+type storyService struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return storyService{
+		repo: repo,
+	}
+}
+
+func (s storyService) CreateStory(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateStoryRequest) (internal.Story, error) {
+	story, err := s.repo.CreateStory(ctx, internal.Story{
+		UserID:      reqUri.UserId,
+		PictureLink: reqBody.PictureLink,
+		ExpiresAt:   time.Now().Add(STORY_DURATION),
+	})
+	if err != nil {
+		return internal.Story{}, err
+	}
+
+	return story, nil
+}
+
+func (s storyService) DeleteStory(ctx context.Context, reqUri StoryUriRequest) error {
+	storyId, _ := uuid.Parse(reqUri.StoryId)
+	return s.repo.DeleteStory(ctx, reqUri.UserId, storyId)
+}
+
+func (s storyService) GetUserStories(ctx context.Context, reqUri OtherUserUriRequest) ([]StoryResponse, error) {
+	stories, err := s.repo.GetActiveStories(ctx, reqUri.OtherUserId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	storyIds := make([]uuid.UUID, 0, len(stories))
+	for _, story := range stories {
+		storyIds = append(storyIds, story.ID)
+	}
+
+	seenStoryIds, err := s.repo.GetSeenStoryIds(ctx, reqUri.UserId, storyIds)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(seenStoryIds))
+	for _, storyId := range seenStoryIds {
+		seen[storyId] = true
+	}
+
+	// Owner sees how many viewed each story
+	var viewCounts map[uuid.UUID]int
+	if reqUri.OtherUserId == reqUri.UserId {
+		counts, err := s.repo.GetViewCounts(ctx, storyIds)
+		if err != nil {
+			return nil, err
+		}
+
+		viewCounts = make(map[uuid.UUID]int, len(counts))
+		for _, count := range counts {
+			viewCounts[count.StoryID] = count.TotalViews
+		}
+	}
+
+	responses := []StoryResponse{}
+	for _, story := range stories {
+		res := StoryResponse{
+			Story:  story,
+			IsSeen: seen[story.ID],
+		}
+
+		if viewCounts != nil {
+			totalViews := viewCounts[story.ID]
+			res.TotalViews = &totalViews
+		}
+
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+func (s storyService) GetTray(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery PaginationQueryRequest) ([]TrayItemResponse, error) {
+	tray, err := s.repo.GetTray(ctx, reqUri.UserId, time.Now(), reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := make([]string, 0, len(tray))
+	for _, item := range tray {
+		userIds = append(userIds, item.UserID)
+	}
+
+	users, err := s.repo.GetUsers(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	usersById := make(map[string]internal.User, len(users))
+	for _, user := range users {
+		usersById[user.ID] = user
+	}
+
+	responses := []TrayItemResponse{}
+	for _, item := range tray {
+		user, ok := usersById[item.UserID]
+		if !ok {
+			continue
+		}
+
+		responses = append(responses, TrayItemResponse{
+			User:          user,
+			LatestAt:      item.LatestAt,
+			TotalStories:  item.TotalStories,
+			UnseenStories: item.UnseenStories,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s storyService) ViewStory(ctx context.Context, reqUri StoryUriRequest) error {
+	storyId, _ := uuid.Parse(reqUri.StoryId)
+
+	story, err := s.repo.GetStory(ctx, storyId)
+	if err != nil {
+		return err
+	}
+
+	if story.ExpiredAt != nil || !story.ExpiresAt.After(time.Now()) {
+		return gorm.ErrRecordNotFound
+	}
+
+	// Owners don't count as viewers
+	if story.UserID == reqUri.UserId {
+		return nil
+	}
+
+	return s.repo.CreateView(ctx, internal.StoryView{
+		StoryID:  storyId,
+		ViewerID: reqUri.UserId,
+	})
+}
+
+func (s storyService) GetViewers(ctx context.Context, reqUri StoryUriRequest, reqQuery PaginationQueryRequest) ([]internal.StoryView, error) {
+	storyId, _ := uuid.Parse(reqUri.StoryId)
+
+	story, err := s.repo.GetStory(ctx, storyId)
+	if err != nil {
+		return nil, err
+	}
+
+	if story.UserID != reqUri.UserId {
+		return nil, ErrNotOwner
+	}
+
+	views, err := s.repo.GetViews(ctx, storyId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (s storyService) ExpireStories(ctx context.Context) error {
+	return s.repo.ExpireStories(ctx, time.Now())
+}
+
+// Drops duplicates, ids are already validated by the request binding.
+func parseIds(ids []string) []uuid.UUID {
+	var (
+		parsedIds = make([]uuid.UUID, 0, len(ids))
+		seen      = make(map[uuid.UUID]bool, len(ids))
+	)
+
+	for _, id := range ids {
+		parsedId, _ := uuid.Parse(id)
+		if seen[parsedId] {
+			continue
+		}
+
+		seen[parsedId] = true
+		parsedIds = append(parsedIds, parsedId)
+	}
+
+	return parsedIds
+}
+
+func (s storyService) getExpiredStories(ctx context.Context, userId string, ids []string) ([]internal.Story, error) {
+	storyIds := parseIds(ids)
+
+	stories, err := s.repo.GetExpiredStories(ctx, userId, storyIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stories) != len(storyIds) {
+		return nil, ErrStoriesNotExpired
+	}
+
+	return stories, nil
+}
+
+func (s storyService) CreateHighlight(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateHighlightRequest) (internal.Highlight, error) {
+	stories, err := s.getExpiredStories(ctx, reqUri.UserId, reqBody.StoryIds)
+	if err != nil {
+		return internal.Highlight{}, err
+	}
+
+	coverLink := reqBody.CoverLink
+	if coverLink == "" {
+		coverLink = stories[0].PictureLink
+	}
+
+	highlight, err := s.repo.CreateHighlight(ctx, internal.Highlight{
+		UserID:    reqUri.UserId,
+		Name:      reqBody.Name,
+		CoverLink: coverLink,
+		Stories:   stories,
+	})
+	if err != nil {
+		return internal.Highlight{}, err
+	}
+
+	return highlight, nil
+}
+
+func (s storyService) UpdateHighlight(ctx context.Context, reqUri HighlightUriRequest, reqBody UpdateHighlightRequest) (internal.Highlight, error) {
+	highlightId, _ := uuid.Parse(reqUri.HighlightId)
+
+	highlight, err := s.repo.GetHighlight(ctx, highlightId)
+	if err != nil {
+		return internal.Highlight{}, err
+	}
+
+	// Other users' highlights are treated as missing
+	if highlight.UserID != reqUri.UserId {
+		return internal.Highlight{}, gorm.ErrRecordNotFound
+	}
+
+	if reqBody.Name != nil {
+		highlight.Name = *reqBody.Name
+	}
+
+	if reqBody.CoverLink != nil {
+		highlight.CoverLink = *reqBody.CoverLink
+	}
+
+	if reqBody.StoryIds != nil {
+		highlight.Stories, err = s.getExpiredStories(ctx, reqUri.UserId, reqBody.StoryIds)
+		if err != nil {
+			return internal.Highlight{}, err
+		}
+	}
+
+	highlight, err = s.repo.UpdateHighlight(ctx, highlight, reqBody.StoryIds != nil)
+	if err != nil {
+		return internal.Highlight{}, err
+	}
+
+	return highlight, nil
+}
+
+func (s storyService) ReorderHighlights(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody ReorderHighlightsRequest) ([]internal.Highlight, error) {
+	highlights, err := s.repo.GetHighlights(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	highlightIds := parseIds(reqBody.HighlightIds)
+	if len(highlightIds) != len(reqBody.HighlightIds) || len(highlightIds) != len(highlights) {
+		return nil, ErrInvalidHighlightOrder
+	}
+
+	owned := make(map[uuid.UUID]bool, len(highlights))
+	for _, highlight := range highlights {
+		owned[highlight.ID] = true
+	}
+
+	for _, highlightId := range highlightIds {
+		if !owned[highlightId] {
+			return nil, ErrInvalidHighlightOrder
+		}
+	}
+
+	if err := s.repo.ReorderHighlights(ctx, reqUri.UserId, highlightIds); err != nil {
+		return nil, err
+	}
+
+	highlights, err = s.repo.GetHighlights(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return highlights, nil
+}
+
+func (s storyService) DeleteHighlight(ctx context.Context, reqUri HighlightUriRequest) error {
+	highlightId, _ := uuid.Parse(reqUri.HighlightId)
+	return s.repo.DeleteHighlight(ctx, reqUri.UserId, highlightId)
+}
@@ -0,0 +1,127 @@
+package story
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rrab-0/its-gram/internal"
+)
+
+const (
+	STORY_DURATION  = 24 * time.Hour
+	EXPIRY_INTERVAL = time.Minute // How often the expiry job runs
+
+	MAXIMUM_HIGHLIGHT_STORIES = 100
+)
+
+type StoryUriRequest struct {
+	UserId  string `uri:"id" binding:"required"`
+	StoryId string `uri:"storyId" binding:"required,uuid"`
+}
+
+type OtherUserUriRequest struct {
+	UserId      string `uri:"id" binding:"required"`
+	OtherUserId string `uri:"otherUserId" binding:"required"`
+}
+
+type CreateStoryRequest struct {
+	PictureLink string `json:"picture_link" binding:"required"`
+}
+
+type HighlightUriRequest struct {
+	UserId      string `uri:"id" binding:"required"`
+	HighlightId string `uri:"highlightId" binding:"required,uuid"`
+}
+
+// Cover defaults to the picture of the first story.
+type CreateHighlightRequest struct {
+	Name      string   `json:"name" binding:"required,max=50"`
+	CoverLink string   `json:"cover_link"`
+	StoryIds  []string `json:"story_ids" binding:"required,min=1,max=100,dive,uuid"`
+}
+
+// Omitted fields are left as is, story_ids replaces the stories of the highlight.
+type UpdateHighlightRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=1,max=50"`
+	CoverLink *string  `json:"cover_link" binding:"omitempty,min=1"`
+	StoryIds  []string `json:"story_ids" binding:"omitempty,min=1,max=100,dive,uuid"`
+}
+
+// Every highlight of the user, in the new order.
+type ReorderHighlightsRequest struct {
+	HighlightIds []string `json:"highlight_ids" binding:"required,min=1,dive,uuid"`
+}
+
+type PaginationQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type TrayQueryRes struct {
+	UserID        string
+	LatestAt      time.Time
+	TotalStories  int
+	UnseenStories int
+}
+
+type ViewCountQueryRes struct {
+	StoryID    uuid.UUID
+	TotalViews int
+}
+
+// A followed user with active stories, some of them not seen yet.
+type TrayItemResponse struct {
+	User          internal.User `json:"user"`
+	LatestAt      time.Time     `json:"latest_at"`
+	TotalStories  int           `json:"total_stories"`
+	UnseenStories int           `json:"unseen_stories"`
+}
+
+type StoryResponse struct {
+	internal.Story
+	IsSeen     bool `json:"is_seen"`
+	TotalViews *int `json:"total_views,omitempty"` // Only for the owner
+}
+
+type Repository interface {
+	CreateStory(ctx context.Context, story internal.Story) (internal.Story, error)
+	GetStory(ctx context.Context, storyId uuid.UUID) (internal.Story, error)
+	DeleteStory(ctx context.Context, userId string, storyId uuid.UUID) error
+	GetActiveStories(ctx context.Context, userId string, now time.Time) ([]internal.Story, error)
+	GetTray(ctx context.Context, userId string, now time.Time, page, limit int) ([]TrayQueryRes, error)
+	GetUsers(ctx context.Context, userIds []string) ([]internal.User, error)
+
+	CreateView(ctx context.Context, view internal.StoryView) error
+	GetSeenStoryIds(ctx context.Context, viewerId string, storyIds []uuid.UUID) ([]uuid.UUID, error)
+	GetViewCounts(ctx context.Context, storyIds []uuid.UUID) ([]ViewCountQueryRes, error)
+	GetViews(ctx context.Context, storyId uuid.UUID, page, limit int) ([]internal.StoryView, error)
+
+	ExpireStories(ctx context.Context, now time.Time) error
+
+	GetExpiredStories(ctx context.Context, userId string, storyIds []uuid.UUID) ([]internal.Story, error)
+	GetHighlight(ctx context.Context, highlightId uuid.UUID) (internal.Highlight, error)
+	GetHighlights(ctx context.Context, userId string) ([]internal.Highlight, error)
+	CreateHighlight(ctx context.Context, highlight internal.Highlight) (internal.Highlight, error)
+	UpdateHighlight(ctx context.Context, highlight internal.Highlight, updateStories bool) (internal.Highlight, error)
+	ReorderHighlights(ctx context.Context, userId string, highlightIds []uuid.UUID) error
+	DeleteHighlight(ctx context.Context, userId string, highlightId uuid.UUID) error
+}
+
+type Service interface {
+	CreateStory(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateStoryRequest) (internal.Story, error)
+	DeleteStory(ctx context.Context, reqUri StoryUriRequest) error
+	GetUserStories(ctx context.Context, reqUri OtherUserUriRequest) ([]StoryResponse, error)
+	GetTray(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery PaginationQueryRequest) ([]TrayItemResponse, error)
+
+	ViewStory(ctx context.Context, reqUri StoryUriRequest) error
+	// Owner only
+	GetViewers(ctx context.Context, reqUri StoryUriRequest, reqQuery PaginationQueryRequest) ([]internal.StoryView, error)
+
+	ExpireStories(ctx context.Context) error
+
+	CreateHighlight(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CreateHighlightRequest) (internal.Highlight, error)
+	UpdateHighlight(ctx context.Context, reqUri HighlightUriRequest, reqBody UpdateHighlightRequest) (internal.Highlight, error)
+	ReorderHighlights(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody ReorderHighlightsRequest) ([]internal.Highlight, error)
+	DeleteHighlight(ctx context.Context, reqUri HighlightUriRequest) error
+}
@@ -32,6 +32,14 @@ type Comment struct {
 	ParentID *uuid.UUID `json:"-" gorm:"type:uuid"`
 }
 
+// Post statuses, only published posts are visible to other users.
+const (
+	POST_STATUS_DRAFT     = "draft"
+	POST_STATUS_SCHEDULED = "scheduled" // Published by the scheduler at PublishAt
+	POST_STATUS_PUBLISHED = "published"
+	POST_STATUS_ARCHIVED  = "archived" // Hidden from others, keeps CreatedAt to go back where it was
+)
+
 type Post struct {
 	ID        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -51,6 +59,15 @@ type Post struct {
 
 	// "post" has many "comments"
 	Comments []Comment `json:"comments" gorm:"foreignKey:PostID;"`
+
+	Status    string     `json:"status" gorm:"not null;default:published;index"`
+	PublishAt *time.Time `json:"publish_at" gorm:"index"`
+
+	// Reposts and quotes reference the original "post", no foreign key so it can be deleted
+	// without touching them, "repost_of" is then empty while "repost_of_id" stays.
+	Type       string     `json:"type" gorm:"not null;default:post"`
+	RepostOf   *Post      `json:"repost_of" gorm:"foreignKey:RepostOfID;constraint:-"`
+	RepostOfID *uuid.UUID `json:"repost_of_id" gorm:"type:uuid;index"`
 }
 
 type User struct {
@@ -72,6 +89,243 @@ type User struct {
 
 	Followers  []*User `json:"followers" gorm:"many2many:user_followers;"`   // "user" many to many "user"
 	Followings []*User `json:"followings" gorm:"many2many:user_followings;"` // "user" many to many "user"
+
+	Highlights []Highlight `json:"highlights" gorm:"foreignKey:UserID;references:ID"` // "user" has many "highlights"
+}
+
+// Row of "user_liked_posts", the join table of "user"'s LikedPosts and "post"'s Likes.
+// Likes from before reactions existed got the default reaction.
+type PostReaction struct {
+	UserID   string    `json:"-" gorm:"primaryKey"`
+	PostID   uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	Reaction string    `json:"reaction" gorm:"not null;default:like"`
+}
+
+func (PostReaction) TableName() string {
+	return "user_liked_posts"
+}
+
+// Row of "user_liked_comments", same as PostReaction but for comments.
+type CommentReaction struct {
+	UserID    string    `json:"-" gorm:"primaryKey"`
+	CommentID uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	Reaction  string    `json:"reaction" gorm:"not null;default:like"`
+}
+
+func (CommentReaction) TableName() string {
+	return "user_liked_comments"
+}
+
+// Row of "user_followings", the join table of "user"'s Followings. Follows from before
+// CreatedAt existed have none.
+type UserFollowing struct {
+	UserID      string     `json:"-" gorm:"primaryKey"`
+	FollowingID string     `json:"-" gorm:"primaryKey"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+func (UserFollowing) TableName() string {
+	return "user_followings"
+}
+
+// Users that "user" doesn't want to see in their suggestions anymore.
+type DismissedSuggestion struct {
+	UserID          string    `json:"-" gorm:"primaryKey"`
+	DismissedUserID string    `json:"dismissed_user_id" gorm:"primaryKey"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Lowercased "#word"s of a post's title and description, kept apart so hashtag search can use an index.
+// No foreign key on the post, deleted and unpublished posts are skipped when searching.
+type PostHashtag struct {
+	PostID uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	Name   string    `json:"name" gorm:"primaryKey"`
+}
+
+// Latest searches of "user", one row per distinct query.
+type RecentSearch struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID string `json:"-" gorm:"not null;uniqueIndex:idx_recent_searches_user_query"`
+	Query  string `json:"query" gorm:"not null;uniqueIndex:idx_recent_searches_user_query"`
+	Type   string `json:"type" gorm:"not null"`
+}
+
+// Post "user" saved privately. No foreign key on the post, deleted posts are skipped when listing.
+type Bookmark struct {
+	UserID    string    `json:"-" gorm:"primaryKey"`
+	PostID    uuid.UUID `json:"post_id" gorm:"primaryKey;type:uuid"`
+	CreatedAt time.Time `json:"saved_at" gorm:"index"`
+}
+
+// Named group of "user"'s bookmarks, only visible to them.
+type BookmarkCollection struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID string `json:"-" gorm:"not null;index"`
+	Name   string `json:"name" gorm:"not null"`
+}
+
+// Bookmarked post in a collection, a post can be in many collections of the same user.
+type BookmarkCollectionPost struct {
+	CollectionID uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	PostID       uuid.UUID `json:"post_id" gorm:"primaryKey;type:uuid"`
+	CreatedAt    time.Time `json:"added_at" gorm:"index"`
+}
+
+// Something "actor" did that "recipient" should know about.
+type Notification struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// "notification" belongs to "user" (the one being notified)
+	RecipientID string `json:"-" gorm:"not null;index"`
+
+	// "notification" belongs to "user" (the one who did something)
+	Actor   User   `json:"actor" gorm:"foreignKey:ActorID;references:ID"`
+	ActorID string `json:"-" gorm:"not null"`
+
+	Type      string     `json:"type" gorm:"not null"`
+	PostID    *uuid.UUID `json:"post_id" gorm:"type:uuid"`
+	CommentID *uuid.UUID `json:"comment_id" gorm:"type:uuid"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+// Device of "user" that can receive push notifications, token is from FCM.
+type DeviceToken struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID   string `json:"-" gorm:"not null;index"`
+	Token    string `json:"token" gorm:"not null;uniqueIndex"`
+	Platform string `json:"platform" gorm:"not null"`
+}
+
+// Channels "user" wants for a type of notification, see preference package for the types.
+type NotificationPreference struct {
+	UserID    string    `json:"-" gorm:"primaryKey"`
+	Type      string    `json:"type" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	InApp bool `json:"in_app" gorm:"not null"`
+	Push  bool `json:"push" gorm:"not null"`
+	Email bool `json:"email" gorm:"not null"`
+}
+
+// Weekly email digest state of "user", no row means subscribed and never sent.
+type DigestSubscription struct {
+	UserID    string    `json:"-" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	LastSentAt     *time.Time `json:"last_sent_at"`
+	UnsubscribedAt *time.Time `json:"unsubscribed_at"`
+}
+
+// Private conversation between users, see message package.
+type Conversation struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// "<smaller user id>:<bigger user id>", keeps one conversation per pair of users
+	DirectKey     *string   `json:"-" gorm:"uniqueIndex"`
+	LastMessageAt time.Time `json:"last_message_at" gorm:"not null;index"`
+
+	// Only for groups
+	IsGroup     bool   `json:"is_group" gorm:"not null;default:false"`
+	Name        string `json:"name"`
+	PictureLink string `json:"picture_link"`
+
+	// "conversation" has many "participants"
+	Participants []ConversationParticipant `json:"participants" gorm:"foreignKey:ConversationID"`
+}
+
+type ConversationParticipant struct {
+	ConversationID uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	CreatedAt      time.Time `json:"joined_at"`
+	UpdatedAt      time.Time `json:"-"`
+
+	// "participant" belongs to "user"
+	User   User   `json:"user" gorm:"foreignKey:UserID;references:ID"`
+	UserID string `json:"-" gorm:"primaryKey;index"`
+
+	Role string `json:"role" gorm:"not null;default:member"`
+
+	// Messages up to this time count as read, shown to other participants as read receipts
+	LastReadAt *time.Time `json:"last_read_at"`
+}
+
+type Message struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index:idx_messages_conversation_created,priority:2"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// "conversation" has many "messages"
+	ConversationID uuid.UUID `json:"conversation_id" gorm:"type:uuid;not null;index:idx_messages_conversation_created,priority:1"`
+
+	// "message" belongs to "user"
+	Sender   User   `json:"sender" gorm:"foreignKey:SenderID;references:ID"`
+	SenderID string `json:"-" gorm:"not null"`
+
+	Type string `json:"type" gorm:"not null"`
+	Body string `json:"body"`
+
+	// User added or removed in group membership messages
+	TargetUser   *User   `json:"target_user,omitempty" gorm:"foreignKey:TargetUserID;references:ID"`
+	TargetUserID *string `json:"-"`
+
+	// Shared post, no foreign key since posts can be hard deleted. Emptied when the post is purged.
+	PostID *uuid.UUID `json:"post_id,omitempty" gorm:"type:uuid"`
+}
+
+// Image of "user" that's shown for 24 hours, see story package.
+type Story struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// "story" belongs to "user"
+	CreatedBy User   `json:"created_by" gorm:"foreignKey:UserID;references:ID"`
+	UserID    string `json:"-" gorm:"not null;index"`
+
+	PictureLink string    `json:"picture_link" gorm:"not null"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+	// Set by the expiry job, the story is kept for the owner afterwards
+	ExpiredAt *time.Time `json:"expired_at" gorm:"index"`
+}
+
+type StoryView struct {
+	StoryID   uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	CreatedAt time.Time `json:"viewed_at"`
+
+	// "view" belongs to "user"
+	Viewer   User   `json:"viewer" gorm:"foreignKey:ViewerID;references:ID"`
+	ViewerID string `json:"-" gorm:"primaryKey"`
+}
+
+// Named collection of "user"'s expired stories, shown on their profile.
+type Highlight struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID string `json:"-" gorm:"not null;index"`
+
+	Name      string `json:"name" gorm:"not null"`
+	CoverLink string `json:"cover_link" gorm:"not null"`
+	Position  int    `json:"position" gorm:"not null;default:0"` // Order on the profile, lowest first
+
+	// "highlight" many to many "stories"
+	Stories []Story `json:"stories" gorm:"many2many:highlight_stories;"`
 }
 
 type UserIdUriRequest struct {
@@ -100,4 +354,31 @@ type GetPostResponse struct {
 	Likes         []User        `json:"likes"`
 	Comments      []interface{} `json:"comments"`
 	TotalComments int           `json:"total_comments"`
+
+	Type       string      `json:"type"`
+	RepostOfID *uuid.UUID  `json:"repost_of_id"`
+	RepostOf   interface{} `json:"repost_of"` // Post, or DeletedCommentOrPostResponse once unavailable
+}
+
+// Original of a repost or quote, RepostOf is only preloaded while it's published.
+func NewRepostOfResponse(repostOfId uuid.UUID, repostOf *Post) interface{} {
+	switch {
+	case repostOf == nil:
+		// Hard deleted or not published anymore, nothing left but the id
+		return DeletedCommentOrPostResponse{
+			ID:        repostOfId,
+			IsDeleted: true,
+		}
+	case repostOf.DeletedAt.Valid:
+		return DeletedCommentOrPostResponse{
+			ID:        repostOf.ID,
+			CreatedAt: repostOf.CreatedAt,
+			UpdatedAt: repostOf.UpdatedAt,
+			DeletedAt: repostOf.DeletedAt,
+			IsDeleted: true,
+			CreatedBy: repostOf.CreatedBy,
+		}
+	default:
+		return repostOf
+	}
 }
@@ -5,8 +5,10 @@ import (
 	"math"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rrab-0/its-gram/internal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -22,9 +24,10 @@ func NewRepository(db *gorm.DB) Repository {
 	}
 }
 
-func (r gormRepository) CreateUser(ctx context.Context, user internal.User) (internal.User, error) {
-	err := r.db.
-		WithContext(ctx).
+func (r gormRepository) CreateUser(ctx context.Context, user internal.User, preferences []internal.NotificationPreference) (internal.User, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
 		Clauses(
 			clause.OnConflict{ // For delete acc, then register with same email
 				Columns: []clause.Column{{Name: "email"}},
@@ -44,6 +47,21 @@ func (r gormRepository) CreateUser(ctx context.Context, user internal.User) (int
 		Create(&user).
 		Error
 	if err != nil {
+		tx.Rollback()
+		return internal.User{}, err
+	}
+
+	// Keep what the user already chose on login
+	err = tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&preferences).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return internal.User{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return internal.User{}, err
 	}
 
@@ -52,7 +70,20 @@ func (r gormRepository) CreateUser(ctx context.Context, user internal.User) (int
 
 func (r gormRepository) GetUser(ctx context.Context, id string) (internal.User, error) {
 	var user internal.User
-	err := r.db.WithContext(ctx).Preload(clause.Associations).Preload("Posts.CreatedBy").Where("id = ?", id).First(&user).Error
+	err := r.db.
+		WithContext(ctx).
+		Preload(clause.Associations).
+		Preload("Posts", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("Posts.CreatedBy").
+		Preload("Highlights", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, created_at")
+		}).
+		Preload("Highlights.Stories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("stories.created_at")
+		}).
+		Where("id = ?", id).
+		First(&user).
+		Error
 	if err != nil {
 		return internal.User{}, err
 	}
@@ -60,9 +91,31 @@ func (r gormRepository) GetUser(ctx context.Context, id string) (internal.User,
 	return user, nil
 }
 
-func (r gormRepository) SearchUser(ctx context.Context, username string) ([]internal.User, error) {
-	var users []internal.User
-	err := r.db.WithContext(ctx).Where("username LIKE ?", "%"+username+"%").Find(&users).Error
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Matches usernames by trigram similarity (pg_trgm) or substring, case-insensitively,
+// most similar first then most followed.
+func (r gormRepository) SearchUser(ctx context.Context, username string, page, limit int) ([]internal.User, error) {
+	var (
+		users  []internal.User
+		query  = strings.ToLower(username)
+		offset = (page - 1) * limit
+	)
+
+	err := r.db.
+		WithContext(ctx).
+		Where("lower(users.username) % ? OR lower(users.username) LIKE ?", query, "%"+likeEscaper.Replace(query)+"%").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "similarity(lower(users.username), ?) DESC",
+			Vars:               []interface{}{query},
+			WithoutParentheses: true,
+		}}).
+		Order("(SELECT COUNT(*) FROM user_followers WHERE user_followers.user_id = users.id) DESC").
+		Order("users.id").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).
+		Error
 	if err != nil {
 		return []internal.User{}, err
 	}
@@ -80,7 +133,7 @@ func (r gormRepository) GetUserHomepage(ctx context.Context, page, limit int, id
 
 	// Get total posts to validate page request
 	user.ID = id
-	err := tx.Preload("Followings.Posts").First(&user).Error
+	err := tx.Preload("Followings.Posts", "status = ?", internal.POST_STATUS_PUBLISHED).First(&user).Error
 	if err != nil {
 		tx.Rollback()
 		return GetHomepageQueryRes{}, err
@@ -100,6 +153,7 @@ func (r gormRepository) GetUserHomepage(ctx context.Context, page, limit int, id
 	res := tx.
 		Preload("Followings.Posts", func(db *gorm.DB) *gorm.DB {
 			return db.
+				Where("status = ?", internal.POST_STATUS_PUBLISHED).
 				Order("created_at DESC").
 				Offset(offset).
 				Limit(limit)
@@ -107,6 +161,8 @@ func (r gormRepository) GetUserHomepage(ctx context.Context, page, limit int, id
 		Preload("Followings.Posts.CreatedBy").
 		Preload("Followings.Posts.Likes").
 		Preload("Followings.Posts.Comments").
+		Preload("Followings.Posts.RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("Followings.Posts.RepostOf.CreatedBy").
 		First(&user)
 	if res.Error != nil {
 		tx.Rollback()
@@ -140,6 +196,7 @@ func (r gormRepository) GetUserHomepageInitialCursor(ctx context.Context, limit
 		WithContext(ctx).
 		Preload("Followings.Posts", func(db *gorm.DB) *gorm.DB {
 			return db.
+				Where("status = ?", internal.POST_STATUS_PUBLISHED).
 				Where("created_at < ?", time.Now().Add(-(24 * time.Hour)).Format(time.RFC3339Nano)).
 				Order("created_at DESC").
 				Limit(limit)
@@ -147,6 +204,8 @@ func (r gormRepository) GetUserHomepageInitialCursor(ctx context.Context, limit
 		Preload("Followings.Posts.CreatedBy").
 		Preload("Followings.Posts.Likes").
 		Preload("Followings.Posts.Comments").
+		Preload("Followings.Posts.RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("Followings.Posts.RepostOf.CreatedBy").
 		First(&user)
 	if res.Error != nil {
 		return nil, res.Error
@@ -181,6 +240,7 @@ func (r gormRepository) GetUserHomepageCursor(ctx context.Context, cursor string
 	res := r.db.WithContext(ctx).
 		Preload("Followings.Posts", func(db *gorm.DB) *gorm.DB {
 			return db.
+				Where("status = ?", internal.POST_STATUS_PUBLISHED).
 				Where("created_at < ?", cursorTime).
 				Order("created_at DESC").
 				Limit(limit)
@@ -188,6 +248,8 @@ func (r gormRepository) GetUserHomepageCursor(ctx context.Context, cursor string
 		Preload("Followings.Posts.CreatedBy").
 		Preload("Followings.Posts.Likes").
 		Preload("Followings.Posts.Comments").
+		Preload("Followings.Posts.RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("Followings.Posts.RepostOf.CreatedBy").
 		First(&user)
 	if res.Error != nil {
 		return nil, res.Error
@@ -332,6 +394,134 @@ func (r gormRepository) UnfollowOtherUser(ctx context.Context, userId, otherUser
 	return nil
 }
 
+// Weights of each signal when ranking suggestions,
+// following someone your followings follow counts the most.
+const (
+	FRIENDS_OF_FRIENDS_WEIGHT = 3
+	MUTUAL_FOLLOWERS_WEIGHT   = 2
+	SHARED_LIKES_WEIGHT       = 1
+)
+
+// Ranks users that "user" doesn't follow yet by:
+// - friends of friends, how many of user's followings follow them
+// - mutual followers, how many users follow both of them
+// - shared likes, how many posts both of them liked
+// Excludes the user itself, already followed and dismissed users.
+const suggestionsQuery = `
+WITH friends_of_friends AS (
+	SELECT f2.following_id AS user_id, COUNT(*) AS total
+	FROM user_followings f1
+	JOIN user_followings f2 ON f2.user_id = f1.following_id
+	WHERE f1.user_id = @id
+	GROUP BY f2.following_id
+), mutual_followers AS (
+	SELECT f2.user_id, COUNT(*) AS total
+	FROM user_followers f1
+	JOIN user_followers f2 ON f2.follower_id = f1.follower_id
+	WHERE f1.user_id = @id
+	GROUP BY f2.user_id
+), shared_likes AS (
+	SELECT l2.user_id, COUNT(*) AS total
+	FROM user_liked_posts l1
+	JOIN user_liked_posts l2 ON l2.post_id = l1.post_id
+	WHERE l1.user_id = @id
+	GROUP BY l2.user_id
+)
+SELECT
+	users.id AS user_id,
+	COALESCE(friends_of_friends.total, 0) AS friends_of_friends,
+	COALESCE(mutual_followers.total, 0) AS mutual_followers,
+	COALESCE(shared_likes.total, 0) AS shared_likes,
+	COALESCE(friends_of_friends.total, 0) * @fof_weight +
+	COALESCE(mutual_followers.total, 0) * @mutual_weight +
+	COALESCE(shared_likes.total, 0) * @likes_weight AS score
+FROM users
+LEFT JOIN friends_of_friends ON friends_of_friends.user_id = users.id
+LEFT JOIN mutual_followers ON mutual_followers.user_id = users.id
+LEFT JOIN shared_likes ON shared_likes.user_id = users.id
+WHERE users.id <> @id
+	AND users.deleted_at IS NULL
+	AND (friends_of_friends.total IS NOT NULL OR mutual_followers.total IS NOT NULL OR shared_likes.total IS NOT NULL)
+	AND NOT EXISTS (SELECT 1 FROM user_followings WHERE user_followings.user_id = @id AND user_followings.following_id = users.id)
+	AND NOT EXISTS (SELECT 1 FROM dismissed_suggestions WHERE dismissed_suggestions.user_id = @id AND dismissed_suggestions.dismissed_user_id = users.id)
+ORDER BY score DESC, users.id
+LIMIT @limit`
+
+func (r gormRepository) GetSuggestions(ctx context.Context, userId string, limit int) ([]GetSuggestionsResponse, error) {
+	var (
+		ranks       []SuggestedUserQueryRes
+		users       []internal.User
+		suggestions []GetSuggestionsResponse
+	)
+
+	err := r.db.
+		WithContext(ctx).
+		Raw(suggestionsQuery, map[string]interface{}{
+			"id":            userId,
+			"fof_weight":    FRIENDS_OF_FRIENDS_WEIGHT,
+			"mutual_weight": MUTUAL_FOLLOWERS_WEIGHT,
+			"likes_weight":  SHARED_LIKES_WEIGHT,
+			"limit":         limit,
+		}).
+		Scan(&ranks).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ranks) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	userIds := make([]string, 0, len(ranks))
+	for _, rank := range ranks {
+		userIds = append(userIds, rank.UserID)
+	}
+
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	usersById := make(map[string]internal.User, len(users))
+	for _, user := range users {
+		usersById[user.ID] = user
+	}
+
+	// Keep the order of the ranking query
+	for _, rank := range ranks {
+		user, ok := usersById[rank.UserID]
+		if !ok {
+			continue
+		}
+
+		suggestions = append(suggestions, GetSuggestionsResponse{
+			User:             user,
+			FriendsOfFriends: rank.FriendsOfFriends,
+			MutualFollowers:  rank.MutualFollowers,
+			SharedLikes:      rank.SharedLikes,
+			Score:            rank.Score,
+		})
+	}
+
+	return suggestions, nil
+}
+
+func (r gormRepository) DismissSuggestion(ctx context.Context, userId, otherUserId string) error {
+	var otherUser internal.User
+	if err := r.db.WithContext(ctx).Where("id = ?", otherUserId).First(&otherUser).Error; err != nil {
+		return err
+	}
+
+	return r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.DismissedSuggestion{
+			UserID:          userId,
+			DismissedUserID: otherUserId,
+		}).
+		Error
+}
+
 func (r gormRepository) GetPosts(ctx context.Context, userId string) ([]internal.Post, []int, error) {
 	var (
 		totalComments []int
@@ -339,7 +529,12 @@ func (r gormRepository) GetPosts(ctx context.Context, userId string) ([]internal
 		tx            = r.db.WithContext(ctx).Begin()
 	)
 
-	err := tx.Unscoped().Preload("Comments").Where("user_id = ?", userId).Find(&posts).Error
+	err := tx.
+		Unscoped().
+		Preload("Comments").
+		Where("user_id = ? AND status = ? AND deleted_at IS NULL", userId, internal.POST_STATUS_PUBLISHED).
+		Find(&posts).
+		Error
 	if err != nil {
 		tx.Rollback()
 		return nil, nil, err
@@ -358,7 +553,9 @@ func (r gormRepository) GetPosts(ctx context.Context, userId string) ([]internal
 		Preload("Comments.CreatedBy").
 		Preload("Comments.Likes").
 		Preload("Comments.Replies").
-		Where("user_id = ?", userId).
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("user_id = ? AND status = ? AND deleted_at IS NULL", userId, internal.POST_STATUS_PUBLISHED).
 		Find(&posts).
 		Error
 
@@ -397,10 +594,15 @@ func (r gormRepository) GetLikes(ctx context.Context, userId string) ([]any, err
 		WithContext(ctx).
 		Model(&user).
 		Preload("LikedPosts", func(db *gorm.DB) *gorm.DB {
-			return db.Preload(clause.Associations)
+			return db.
+				Where("status = ?", internal.POST_STATUS_PUBLISHED).
+				Preload(clause.Associations).
+				Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED)
 		}).
 		Preload("LikedComments", func(db *gorm.DB) *gorm.DB {
-			return db.Preload(clause.Associations)
+			return db.
+				Preload(clause.Associations).
+				Preload("CreatedIn", "status = ?", internal.POST_STATUS_PUBLISHED)
 		}).
 		First(&user).
 		Error
@@ -434,9 +636,271 @@ func (r gormRepository) GetLikes(ctx context.Context, userId string) ([]any, err
 func (r gormRepository) GetComments(ctx context.Context, userId string) ([]internal.Comment, error) {
 	var comments []internal.Comment
 
-	if err := r.db.WithContext(ctx).Preload(clause.Associations).Where("user_id = ?", userId).Find(&comments).Error; err != nil {
+	err := r.db.
+		WithContext(ctx).
+		Preload(clause.Associations).
+		Preload("CreatedIn", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Where("user_id = ?", userId).
+		Find(&comments).
+		Error
+	if err != nil {
 		return []internal.Comment{}, err
 	}
 
 	return comments, nil
 }
+
+func (r gormRepository) PostExists(ctx context.Context, postId uuid.UUID) (bool, error) {
+	var count int64
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&internal.Post{}).
+		Where("id = ? AND status = ?", postId, internal.POST_STATUS_PUBLISHED).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r gormRepository) GetPostsByIds(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error) {
+	var posts []internal.Post
+
+	if len(postIds) == 0 {
+		return posts, nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("CreatedBy").
+		Preload("Likes").
+		Preload("Comments").
+		Preload("RepostOf", "status = ?", internal.POST_STATUS_PUBLISHED).
+		Preload("RepostOf.CreatedBy").
+		Where("id IN ? AND status = ?", postIds, internal.POST_STATUS_PUBLISHED).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r gormRepository) SavePost(ctx context.Context, userId string, postId uuid.UUID) error {
+	return r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.Bookmark{UserID: userId, PostID: postId}).
+		Error
+}
+
+// Also removes the post from the user's collections.
+func (r gormRepository) UnsavePost(ctx context.Context, userId string, postId uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
+		Where("post_id = ? AND collection_id IN (?)", postId, tx.Model(&internal.BookmarkCollection{}).Select("id").Where("user_id = ?", userId)).
+		Delete(&internal.BookmarkCollectionPost{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	res := tx.Where("user_id = ? AND post_id = ?", userId, postId).Delete(&internal.Bookmark{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Latest saved first, skips deleted posts.
+func (r gormRepository) GetBookmarks(ctx context.Context, userId string, page, limit int) ([]internal.Bookmark, error) {
+	var bookmarks []internal.Bookmark
+
+	err := r.db.
+		WithContext(ctx).
+		Joins("JOIN posts ON posts.id = bookmarks.post_id AND posts.deleted_at IS NULL AND posts.status = ?", internal.POST_STATUS_PUBLISHED).
+		Where("bookmarks.user_id = ?", userId).
+		Order("bookmarks.created_at DESC, bookmarks.post_id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&bookmarks).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+func (r gormRepository) CreateCollection(ctx context.Context, collection internal.BookmarkCollection) (internal.BookmarkCollection, error) {
+	if err := r.db.WithContext(ctx).Create(&collection).Error; err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	return collection, nil
+}
+
+func (r gormRepository) GetCollection(ctx context.Context, collectionId uuid.UUID) (internal.BookmarkCollection, error) {
+	var collection internal.BookmarkCollection
+
+	if err := r.db.WithContext(ctx).Where("id = ?", collectionId).First(&collection).Error; err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	return collection, nil
+}
+
+// Oldest first, with how many (not deleted) posts each collection has.
+func (r gormRepository) GetCollections(ctx context.Context, userId string) ([]internal.BookmarkCollection, []CollectionCountQueryRes, error) {
+	var (
+		collections []internal.BookmarkCollection
+		counts      []CollectionCountQueryRes
+	)
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("created_at, id").Find(&collections).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(collections) == 0 {
+		return collections, counts, nil
+	}
+
+	collectionIds := make([]uuid.UUID, 0, len(collections))
+	for _, collection := range collections {
+		collectionIds = append(collectionIds, collection.ID)
+	}
+
+	err = r.db.
+		WithContext(ctx).
+		Model(&internal.BookmarkCollectionPost{}).
+		Select("bookmark_collection_posts.collection_id, COUNT(*) AS total_posts").
+		Joins("JOIN posts ON posts.id = bookmark_collection_posts.post_id AND posts.deleted_at IS NULL AND posts.status = ?", internal.POST_STATUS_PUBLISHED).
+		Where("bookmark_collection_posts.collection_id IN ?", collectionIds).
+		Group("bookmark_collection_posts.collection_id").
+		Scan(&counts).
+		Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return collections, counts, nil
+}
+
+func (r gormRepository) RenameCollection(ctx context.Context, collectionId uuid.UUID, name string) (internal.BookmarkCollection, error) {
+	err := r.db.WithContext(ctx).Model(&internal.BookmarkCollection{}).Where("id = ?", collectionId).Update("name", name).Error
+	if err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	return r.GetCollection(ctx, collectionId)
+}
+
+// Posts stay saved, only the collection is gone.
+func (r gormRepository) DeleteCollection(ctx context.Context, userId string, collectionId uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	res := tx.Where("id = ? AND user_id = ?", collectionId, userId).Delete(&internal.BookmarkCollection{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Where("collection_id = ?", collectionId).Delete(&internal.BookmarkCollectionPost{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Saves the post too if it isn't yet.
+func (r gormRepository) AddCollectionPost(ctx context.Context, userId string, collectionId, postId uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	err := tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.Bookmark{UserID: userId, PostID: postId}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&internal.BookmarkCollectionPost{CollectionID: collectionId, PostID: postId}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r gormRepository) RemoveCollectionPost(ctx context.Context, collectionId, postId uuid.UUID) error {
+	res := r.db.
+		WithContext(ctx).
+		Where("collection_id = ? AND post_id = ?", collectionId, postId).
+		Delete(&internal.BookmarkCollectionPost{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Latest added first, skips deleted posts.
+func (r gormRepository) GetCollectionPosts(ctx context.Context, collectionId uuid.UUID, page, limit int) ([]internal.BookmarkCollectionPost, error) {
+	var collectionPosts []internal.BookmarkCollectionPost
+
+	err := r.db.
+		WithContext(ctx).
+		Joins("JOIN posts ON posts.id = bookmark_collection_posts.post_id AND posts.deleted_at IS NULL AND posts.status = ?", internal.POST_STATUS_PUBLISHED).
+		Where("bookmark_collection_posts.collection_id = ?", collectionId).
+		Order("bookmark_collection_posts.created_at DESC, bookmark_collection_posts.post_id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&collectionPosts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return collectionPosts, nil
+}
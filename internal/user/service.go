@@ -2,17 +2,25 @@ package user
 
 import (
 	"context"
+	"log"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/notification"
+	"github.com/rrab-0/its-gram/internal/preference"
+	"gorm.io/gorm"
 )
 
 type userService struct {
-	repo Repository
+	repo     Repository
+	notifier notification.Notifier
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, notifier notification.Notifier) Service {
 	return userService{
-		repo: repo,
+		repo:     repo,
+		notifier: notifier,
 	}
 }
 
@@ -24,7 +32,7 @@ func (s userService) CreateUser(ctx context.Context, firebaseId, username, email
 		PictureLink: picture,
 	}
 
-	user, err := s.repo.CreateUser(ctx, user)
+	user, err := s.repo.CreateUser(ctx, user, preference.Defaults(firebaseId))
 	if err != nil {
 		return internal.User{}, err
 	}
@@ -42,7 +50,7 @@ func (s userService) GetUser(ctx context.Context, reqUri internal.UserIdUriReque
 }
 
 func (s userService) SearchUser(ctx context.Context, reqQuery UserSearchRequest) ([]internal.User, error) {
-	users, err := s.repo.SearchUser(ctx, reqQuery.Username)
+	users, err := s.repo.SearchUser(ctx, reqQuery.Username, reqQuery.Page, reqQuery.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -96,11 +104,51 @@ func (s userService) DeleteUser(ctx context.Context, reqUri internal.UserIdUriRe
 }
 
 func (s userService) FollowOtherUser(ctx context.Context, reqUri FollowOtherUserRequest) error {
-	return s.repo.FollowOtherUser(ctx, reqUri.UserId, reqUri.OtherUserId)
+	if err := s.repo.FollowOtherUser(ctx, reqUri.UserId, reqUri.OtherUserId); err != nil {
+		return err
+	}
+
+	// Notifications are best effort, failing to send one shouldn't fail the follow itself.
+	err := s.notifier.Notify(ctx, notification.Event{
+		Type:        notification.TYPE_FOLLOW,
+		ActorID:     reqUri.UserId,
+		RecipientID: reqUri.OtherUserId,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to send %v notification: %v", notification.TYPE_FOLLOW, err.Error())
+	}
+
+	return nil
 }
 
 func (s userService) UnfollowOtherUser(ctx context.Context, reqUri FollowOtherUserRequest) error {
-	return s.repo.UnfollowOtherUser(ctx, reqUri.UserId, reqUri.OtherUserId)
+	if err := s.repo.UnfollowOtherUser(ctx, reqUri.UserId, reqUri.OtherUserId); err != nil {
+		return err
+	}
+
+	err := s.notifier.Retract(ctx, notification.Event{
+		Type:        notification.TYPE_FOLLOW,
+		ActorID:     reqUri.UserId,
+		RecipientID: reqUri.OtherUserId,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to retract %v notification: %v", notification.TYPE_FOLLOW, err.Error())
+	}
+
+	return nil
+}
+
+func (s userService) GetSuggestions(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetSuggestionsQueryRequest) ([]GetSuggestionsResponse, error) {
+	suggestions, err := s.repo.GetSuggestions(ctx, reqUri.UserId, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (s userService) DismissSuggestion(ctx context.Context, reqUri DismissSuggestionRequest) error {
+	return s.repo.DismissSuggestion(ctx, reqUri.UserId, reqUri.OtherUserId)
 }
 
 func (s userService) GetPosts(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.Post, []int, error) {
@@ -129,3 +177,194 @@ func (s userService) GetComments(ctx context.Context, reqUri internal.UserIdUriR
 
 	return comments, nil
 }
+
+func (s userService) SavePost(ctx context.Context, reqUri BookmarkUriRequest) error {
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	exists, err := s.repo.PostExists(ctx, postId)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+
+	return s.repo.SavePost(ctx, reqUri.UserId, postId)
+}
+
+func (s userService) UnsavePost(ctx context.Context, reqUri BookmarkUriRequest) error {
+	postId, _ := uuid.Parse(reqUri.PostId)
+	return s.repo.UnsavePost(ctx, reqUri.UserId, postId)
+}
+
+// Keeps the order of postIds, posts deleted in the meantime are skipped.
+func (s userService) toSavedPosts(ctx context.Context, postIds []uuid.UUID, savedAts []time.Time) ([]SavedPostResponse, error) {
+	posts, err := s.repo.GetPostsByIds(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	postsById := make(map[uuid.UUID]internal.Post, len(posts))
+	for _, post := range posts {
+		postsById[post.ID] = post
+	}
+
+	responses := []SavedPostResponse{}
+	for i, postId := range postIds {
+		post, ok := postsById[postId]
+		if !ok {
+			continue
+		}
+
+		responses = append(responses, SavedPostResponse{
+			SavedAt: savedAts[i],
+			Post:    post,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s userService) GetBookmarks(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetBookmarksQueryRequest) ([]SavedPostResponse, error) {
+	bookmarks, err := s.repo.GetBookmarks(ctx, reqUri.UserId, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		postIds  = make([]uuid.UUID, 0, len(bookmarks))
+		savedAts = make([]time.Time, 0, len(bookmarks))
+	)
+
+	for _, bookmark := range bookmarks {
+		postIds = append(postIds, bookmark.PostID)
+		savedAts = append(savedAts, bookmark.CreatedAt)
+	}
+
+	return s.toSavedPosts(ctx, postIds, savedAts)
+}
+
+func (s userService) CreateCollection(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CollectionRequest) (internal.BookmarkCollection, error) {
+	collection, err := s.repo.CreateCollection(ctx, internal.BookmarkCollection{
+		UserID: reqUri.UserId,
+		Name:   reqBody.Name,
+	})
+	if err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	return collection, nil
+}
+
+func (s userService) GetCollections(ctx context.Context, reqUri internal.UserIdUriRequest) ([]CollectionResponse, error) {
+	collections, counts, err := s.repo.GetCollections(ctx, reqUri.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPosts := make(map[uuid.UUID]int, len(counts))
+	for _, count := range counts {
+		totalPosts[count.CollectionID] = count.TotalPosts
+	}
+
+	responses := []CollectionResponse{}
+	for _, collection := range collections {
+		responses = append(responses, CollectionResponse{
+			BookmarkCollection: collection,
+			TotalPosts:         totalPosts[collection.ID],
+		})
+	}
+
+	return responses, nil
+}
+
+// Other users' collections are treated as missing.
+func (s userService) getOwnCollection(ctx context.Context, userId, collectionId string) (internal.BookmarkCollection, error) {
+	id, _ := uuid.Parse(collectionId)
+
+	collection, err := s.repo.GetCollection(ctx, id)
+	if err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	if collection.UserID != userId {
+		return internal.BookmarkCollection{}, gorm.ErrRecordNotFound
+	}
+
+	return collection, nil
+}
+
+func (s userService) RenameCollection(ctx context.Context, reqUri CollectionUriRequest, reqBody CollectionRequest) (internal.BookmarkCollection, error) {
+	collection, err := s.getOwnCollection(ctx, reqUri.UserId, reqUri.CollectionId)
+	if err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	collection, err = s.repo.RenameCollection(ctx, collection.ID, reqBody.Name)
+	if err != nil {
+		return internal.BookmarkCollection{}, err
+	}
+
+	return collection, nil
+}
+
+func (s userService) DeleteCollection(ctx context.Context, reqUri CollectionUriRequest) error {
+	collectionId, _ := uuid.Parse(reqUri.CollectionId)
+	return s.repo.DeleteCollection(ctx, reqUri.UserId, collectionId)
+}
+
+func (s userService) AddPostToCollection(ctx context.Context, reqUri CollectionPostUriRequest) error {
+	collection, err := s.getOwnCollection(ctx, reqUri.UserId, reqUri.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	postId, _ := uuid.Parse(reqUri.PostId)
+
+	exists, err := s.repo.PostExists(ctx, postId)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+
+	return s.repo.AddCollectionPost(ctx, reqUri.UserId, collection.ID, postId)
+}
+
+// The post stays saved.
+func (s userService) RemovePostFromCollection(ctx context.Context, reqUri CollectionPostUriRequest) error {
+	collection, err := s.getOwnCollection(ctx, reqUri.UserId, reqUri.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	postId, _ := uuid.Parse(reqUri.PostId)
+	return s.repo.RemoveCollectionPost(ctx, collection.ID, postId)
+}
+
+func (s userService) GetCollectionPosts(ctx context.Context, reqUri CollectionUriRequest, reqQuery GetBookmarksQueryRequest) ([]SavedPostResponse, error) {
+	collection, err := s.getOwnCollection(ctx, reqUri.UserId, reqUri.CollectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionPosts, err := s.repo.GetCollectionPosts(ctx, collection.ID, reqQuery.Page, reqQuery.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		postIds  = make([]uuid.UUID, 0, len(collectionPosts))
+		addedAts = make([]time.Time, 0, len(collectionPosts))
+	)
+
+	for _, collectionPost := range collectionPosts {
+		postIds = append(postIds, collectionPost.PostID)
+		addedAts = append(addedAts, collectionPost.CreatedAt)
+	}
+
+	return s.toSavedPosts(ctx, postIds, addedAts)
+}
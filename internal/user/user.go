@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rrab-0/its-gram/internal"
 )
 
@@ -13,7 +15,9 @@ type UpdateUserProfileRequest struct {
 }
 
 type UserSearchRequest struct {
-	Username string `form:"username" binding:"required"`
+	Username string `form:"username" binding:"required,min=2"`
+	Page     int    `form:"page"`
+	Limit    int    `form:"limit"`
 }
 
 type FollowOtherUserRequest struct {
@@ -59,23 +63,106 @@ type GetUserHomepageInitialCursorQueryRequest struct {
 	Limit int `form:"limit" binding:"required"`
 }
 
+type GetSuggestionsQueryRequest struct {
+	Limit int `form:"limit"`
+}
+
+type DismissSuggestionRequest struct {
+	UserId      string `uri:"id" binding:"required"`
+	OtherUserId string `uri:"otherUserId" binding:"required"`
+}
+
+type SuggestedUserQueryRes struct {
+	UserID           string
+	FriendsOfFriends int
+	MutualFollowers  int
+	SharedLikes      int
+	Score            int
+}
+
+type GetSuggestionsResponse struct {
+	User             internal.User `json:"user"`
+	FriendsOfFriends int           `json:"friends_of_friends"`
+	MutualFollowers  int           `json:"mutual_followers"`
+	SharedLikes      int           `json:"shared_likes"`
+	Score            int           `json:"score"`
+}
+
+type BookmarkUriRequest struct {
+	UserId string `uri:"id" binding:"required"`
+	PostId string `uri:"postId" binding:"required,uuid"`
+}
+
+type CollectionUriRequest struct {
+	UserId       string `uri:"id" binding:"required"`
+	CollectionId string `uri:"collectionId" binding:"required,uuid"`
+}
+
+type CollectionPostUriRequest struct {
+	UserId       string `uri:"id" binding:"required"`
+	CollectionId string `uri:"collectionId" binding:"required,uuid"`
+	PostId       string `uri:"postId" binding:"required,uuid"`
+}
+
+type CollectionRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type GetBookmarksQueryRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type CollectionCountQueryRes struct {
+	CollectionID uuid.UUID
+	TotalPosts   int
+}
+
+type SavedPostResponse struct {
+	SavedAt time.Time     `json:"saved_at"`
+	Post    internal.Post `json:"post"`
+}
+
+type CollectionResponse struct {
+	internal.BookmarkCollection
+	TotalPosts int `json:"total_posts"`
+}
+
 type Repository interface {
 	GetUser(ctx context.Context, id string) (internal.User, error)
-	SearchUser(ctx context.Context, username string) ([]internal.User, error)
+	SearchUser(ctx context.Context, username string, page, limit int) ([]internal.User, error)
 	GetUserHomepage(ctx context.Context, page, limit int, id string) (GetHomepageQueryRes, error)
 	GetUserHomepageInitialCursor(ctx context.Context, limit int, id string) (*GetUserHomepageCursorQueryRes, error)
 	GetUserHomepageCursor(ctx context.Context, cursor string, limit int, id string) (*GetUserHomepageCursorQueryRes, error)
 
-	CreateUser(ctx context.Context, user internal.User) (internal.User, error)
+	CreateUser(ctx context.Context, user internal.User, preferences []internal.NotificationPreference) (internal.User, error)
 	UpdateUserProfile(ctx context.Context, id, username, picture, description string) (internal.User, error)
 	DeleteUser(ctx context.Context, id string) (internal.User, error)
 
 	FollowOtherUser(ctx context.Context, userId, otherUserId string) error
 	UnfollowOtherUser(ctx context.Context, userId, otherUserId string) error
 
+	GetSuggestions(ctx context.Context, userId string, limit int) ([]GetSuggestionsResponse, error)
+	DismissSuggestion(ctx context.Context, userId, otherUserId string) error
+
 	GetLikes(ctx context.Context, userId string) ([]any, error)
 	GetPosts(ctx context.Context, userId string) ([]internal.Post, []int, error)
 	GetComments(ctx context.Context, userId string) ([]internal.Comment, error)
+
+	PostExists(ctx context.Context, postId uuid.UUID) (bool, error)
+	GetPostsByIds(ctx context.Context, postIds []uuid.UUID) ([]internal.Post, error)
+	SavePost(ctx context.Context, userId string, postId uuid.UUID) error
+	UnsavePost(ctx context.Context, userId string, postId uuid.UUID) error
+	GetBookmarks(ctx context.Context, userId string, page, limit int) ([]internal.Bookmark, error)
+
+	CreateCollection(ctx context.Context, collection internal.BookmarkCollection) (internal.BookmarkCollection, error)
+	GetCollection(ctx context.Context, collectionId uuid.UUID) (internal.BookmarkCollection, error)
+	GetCollections(ctx context.Context, userId string) ([]internal.BookmarkCollection, []CollectionCountQueryRes, error)
+	RenameCollection(ctx context.Context, collectionId uuid.UUID, name string) (internal.BookmarkCollection, error)
+	DeleteCollection(ctx context.Context, userId string, collectionId uuid.UUID) error
+	AddCollectionPost(ctx context.Context, userId string, collectionId, postId uuid.UUID) error
+	RemoveCollectionPost(ctx context.Context, collectionId, postId uuid.UUID) error
+	GetCollectionPosts(ctx context.Context, collectionId uuid.UUID, page, limit int) ([]internal.BookmarkCollectionPost, error)
 }
 
 type Service interface {
@@ -92,7 +179,23 @@ type Service interface {
 	FollowOtherUser(ctx context.Context, reqUri FollowOtherUserRequest) error
 	UnfollowOtherUser(ctx context.Context, reqUri FollowOtherUserRequest) error
 
+	GetSuggestions(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetSuggestionsQueryRequest) ([]GetSuggestionsResponse, error)
+	DismissSuggestion(ctx context.Context, reqUri DismissSuggestionRequest) error
+
 	GetLikes(ctx context.Context, reqUri internal.UserIdUriRequest) ([]any, error)
 	GetPosts(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.Post, []int, error)
 	GetComments(ctx context.Context, reqUri internal.UserIdUriRequest) ([]internal.Comment, error)
+
+	// Bookmarks are private, every method only works on the user's own
+	SavePost(ctx context.Context, reqUri BookmarkUriRequest) error
+	UnsavePost(ctx context.Context, reqUri BookmarkUriRequest) error
+	GetBookmarks(ctx context.Context, reqUri internal.UserIdUriRequest, reqQuery GetBookmarksQueryRequest) ([]SavedPostResponse, error)
+
+	CreateCollection(ctx context.Context, reqUri internal.UserIdUriRequest, reqBody CollectionRequest) (internal.BookmarkCollection, error)
+	GetCollections(ctx context.Context, reqUri internal.UserIdUriRequest) ([]CollectionResponse, error)
+	RenameCollection(ctx context.Context, reqUri CollectionUriRequest, reqBody CollectionRequest) (internal.BookmarkCollection, error)
+	DeleteCollection(ctx context.Context, reqUri CollectionUriRequest) error
+	AddPostToCollection(ctx context.Context, reqUri CollectionPostUriRequest) error
+	RemovePostFromCollection(ctx context.Context, reqUri CollectionPostUriRequest) error
+	GetCollectionPosts(ctx context.Context, reqUri CollectionUriRequest, reqQuery GetBookmarksQueryRequest) ([]SavedPostResponse, error)
 }
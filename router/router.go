@@ -4,14 +4,22 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/rrab-0/its-gram/internal"
+	"github.com/rrab-0/its-gram/internal/digest"
+	"github.com/rrab-0/its-gram/internal/message"
+	"github.com/rrab-0/its-gram/internal/notification"
 	"github.com/rrab-0/its-gram/internal/post"
+	"github.com/rrab-0/its-gram/internal/preference"
+	"github.com/rrab-0/its-gram/internal/push"
+	"github.com/rrab-0/its-gram/internal/realtime"
+	"github.com/rrab-0/its-gram/internal/search"
+	"github.com/rrab-0/its-gram/internal/story"
 	"github.com/rrab-0/its-gram/internal/user"
 	"github.com/spf13/viper"
 	// swaggerFiles "github.com/swaggo/files"
 	// ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.Handler, postHandler post.Handler) {
+func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.Handler, postHandler post.Handler, searchHandler search.Handler, notificationHandler notification.Handler, realtimeHandler realtime.Handler, pushHandler push.Handler, preferenceHandler preference.Handler, digestHandler digest.Handler, messageHandler message.Handler, storyHandler story.Handler) {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{ /* "http://localhost:5173" */ "*"},
 		AllowMethods:     []string{"OPTIONS", "POST", "GET", "PUT", "PATCH", "DELETE"},
@@ -25,19 +33,27 @@ func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.
 	var (
 		validateRegisterToken gin.HandlerFunc
 		validateToken         gin.HandlerFunc
+		validateOptionalToken gin.HandlerFunc
+		validateSocketToken   gin.HandlerFunc
 	)
 
 	if viper.GetString("ENV") == "LOCAL_DEV" {
 		validateRegisterToken = firebaseAuth.ValidateDevToken("REGISTER")
 		validateToken = firebaseAuth.ValidateDevToken("")
+		validateOptionalToken = firebaseAuth.ValidateOptionalDevToken()
+		validateSocketToken = firebaseAuth.ValidateDevToken("")
 	} else if viper.GetString("ENV") == "NGROK_DEV" {
 		// validateRegisterToken = firebaseAuth.ValidateNgrokDevToken("REGISTER")
 		// validateToken = firebaseAuth.ValidateNgrokDevToken("")
 		validateRegisterToken = firebaseAuth.ValidateToken("REGISTER")
 		validateToken = firebaseAuth.ValidateToken("")
+		validateOptionalToken = firebaseAuth.ValidateOptionalToken()
+		validateSocketToken = firebaseAuth.ValidateWebSocketToken()
 	} else {
 		validateRegisterToken = firebaseAuth.ValidateToken("REGISTER")
 		validateToken = firebaseAuth.ValidateToken("")
+		validateOptionalToken = firebaseAuth.ValidateOptionalToken()
+		validateSocketToken = firebaseAuth.ValidateWebSocketToken()
 	}
 
 	r.Static("/static", "./web")
@@ -58,7 +74,7 @@ func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.
 		user.GET("/:id/posts", userHandler.GetPosts)
 		user.GET("/:id/comments", userHandler.GetComments)
 		user.GET("/:id/likes", userHandler.GetLikes)
-		user.GET("/search", userHandler.SearchUser)
+		user.GET("/search", validateOptionalToken, searchHandler.RecordSearch("username", search.TYPE_USER), userHandler.SearchUser)
 
 		user.Use(validateToken)
 		user.GET("/:id/homepage", userHandler.GetUserHomepage)
@@ -69,20 +85,58 @@ func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.
 
 		user.POST("/:id/follow/:otherUserId", userHandler.FollowOtherUser)
 		user.DELETE("/:id/unfollow/:otherUserId", userHandler.UnfollowOtherUser)
+
+		user.GET("/:id/suggestions", userHandler.GetSuggestions)
+		user.POST("/:id/suggestions/dismiss/:otherUserId", userHandler.DismissSuggestion)
+
+		user.GET("/:id/bookmarks", userHandler.GetBookmarks)
+		user.POST("/:id/bookmarks/:postId", userHandler.SavePost)
+		user.DELETE("/:id/bookmarks/:postId", userHandler.UnsavePost)
+
+		user.GET("/:id/collections", userHandler.GetCollections)
+		user.POST("/:id/collections", userHandler.CreateCollection)
+		user.PATCH("/:id/collections/:collectionId", userHandler.RenameCollection)
+		user.DELETE("/:id/collections/:collectionId", userHandler.DeleteCollection)
+		user.GET("/:id/collections/:collectionId/posts", userHandler.GetCollectionPosts)
+		user.POST("/:id/collections/:collectionId/posts/:postId", userHandler.AddPostToCollection)
+		user.DELETE("/:id/collections/:collectionId/posts/:postId", userHandler.RemovePostFromCollection)
 	}
 
 	post := v1.Group("/post")
 	{
+		post.GET("/search", validateOptionalToken, searchHandler.RecordSearch("q", search.TYPE_POST), postHandler.SearchPosts)
+		post.GET("/hashtag/search", validateOptionalToken, searchHandler.RecordSearch("q", search.TYPE_HASHTAG), postHandler.SearchHashtags)
 		post.GET("/:id", postHandler.GetPostById)
 		post.GET("/comment/:commentId", postHandler.GetComment)
 
 		post.Use(validateToken)
 		post.POST("/create/:id", postHandler.CreatePost)
 		post.DELETE("/:postId/user/:id/delete", postHandler.DeletePost)
+		post.POST("/:postId/user/:id/archive", postHandler.ArchivePost)
+		post.POST("/:postId/user/:id/unarchive", postHandler.UnarchivePost)
+		post.GET("/user/:id/archived", postHandler.GetArchivedPosts)
+
+		post.GET("/user/:id/trash", postHandler.GetTrash)
+		post.POST("/user/:id/trash/posts/:postId/restore", postHandler.RestorePost)
+		post.POST("/user/:id/trash/comments/:commentId/restore", postHandler.RestoreComment)
+
+		post.GET("/user/:id/drafts", postHandler.GetDrafts)
+		post.POST("/user/:id/drafts", postHandler.CreateDraft)
+		post.PATCH("/user/:id/drafts/:postId", postHandler.UpdateDraft)
+		post.POST("/user/:id/drafts/:postId/publish", postHandler.PublishDraft)
+
+		post.GET("/user/:id/scheduled", postHandler.GetScheduledPosts)
+		post.POST("/user/:id/scheduled", postHandler.SchedulePost)
+		post.PATCH("/user/:id/scheduled/:postId", postHandler.ReschedulePost)
+		post.POST("/user/:id/scheduled/:postId/cancel", postHandler.CancelScheduledPost)
 
 		post.POST("/:postId/user/:id/like", postHandler.LikePost)
 		post.DELETE("/:postId/user/:id/unlike", postHandler.UnlikePost)
 
+		post.POST("/:postId/user/:id/repost", postHandler.RepostPost)
+		post.DELETE("/:postId/user/:id/unrepost", postHandler.UndoRepost)
+		post.POST("/:postId/user/:id/quote", postHandler.QuotePost)
+
 		post.POST("/:postId/user/:id/comment", postHandler.CommentPost)
 		post.DELETE("/user/:id/comment/remove/:commentId", postHandler.UncommentPost)
 
@@ -91,5 +145,93 @@ func Setup(r *gin.Engine, firebaseAuth *internal.FirebaseAuth, userHandler user.
 
 		post.POST("/user/:id/comment/like/:commentId", postHandler.LikeComment)
 		post.DELETE("/user/:id/comment/unlike/:commentId", postHandler.UnlikeComment)
+
+		// Unlike removes any reaction
+		post.PUT("/:postId/user/:id/reaction", postHandler.ReactPost)
+		post.GET("/user/:id/reactions/:postId", postHandler.GetPostReactions)
+		post.PUT("/user/:id/comment/reaction/:commentId", postHandler.ReactComment)
+		post.GET("/user/:id/comment/reactions/:commentId", postHandler.GetCommentReactions)
+	}
+
+	searchGroup := v1.Group("/search")
+	{
+		searchGroup.GET("", validateOptionalToken, searchHandler.RecordSearch("q", ""), searchHandler.Search)
+
+		searchGroup.Use(validateToken)
+		searchGroup.GET("/:id/recent", searchHandler.GetRecentSearches)
+		searchGroup.DELETE("/:id/recent", searchHandler.ClearRecentSearches)
+		searchGroup.DELETE("/:id/recent/:searchId", searchHandler.DeleteRecentSearch)
+	}
+
+	notificationGroup := v1.Group("/notification")
+	{
+		notificationGroup.Use(validateToken)
+		notificationGroup.GET("/:id", notificationHandler.GetNotifications)
+		notificationGroup.GET("/:id/unread-count", notificationHandler.GetUnreadCount)
+		notificationGroup.PATCH("/:id/read", notificationHandler.MarkAllAsRead)
+		notificationGroup.PATCH("/:id/read/:notificationId", notificationHandler.MarkAsRead)
+
+		notificationGroup.GET("/:id/preferences", preferenceHandler.GetPreferences)
+		notificationGroup.PATCH("/:id/preferences", preferenceHandler.UpdatePreferences)
+	}
+
+	realtimeGroup := v1.Group("/realtime")
+	{
+		realtimeGroup.GET("/:id/ws", validateSocketToken, realtimeHandler.ServeWebSocket)
+
+		realtimeGroup.Use(validateToken)
+		realtimeGroup.GET("/:id/events", realtimeHandler.StreamEvents)
+	}
+
+	pushGroup := v1.Group("/push")
+	{
+		pushGroup.Use(validateToken)
+		pushGroup.GET("/:id/devices", pushHandler.GetDevices)
+		pushGroup.POST("/:id/devices", pushHandler.RegisterDevice)
+		pushGroup.DELETE("/:id/devices/:deviceId", pushHandler.DeleteDevice)
+	}
+
+	digestGroup := v1.Group("/digest")
+	{
+		// GET for the link in the email, POST for one-click unsubscribe (RFC 8058)
+		digestGroup.GET("/unsubscribe/:id", digestHandler.Unsubscribe)
+		digestGroup.POST("/unsubscribe/:id", digestHandler.Unsubscribe)
+	}
+
+	messageGroup := v1.Group("/message")
+	{
+		messageGroup.Use(validateToken)
+		messageGroup.GET("/:id/conversations", messageHandler.GetConversations)
+		messageGroup.POST("/:id/conversations", messageHandler.CreateConversation)
+		messageGroup.GET("/:id/unread-count", messageHandler.GetUnreadCount)
+
+		messageGroup.GET("/:id/conversations/:conversationId/messages", messageHandler.GetMessages)
+		messageGroup.POST("/:id/conversations/:conversationId/messages", messageHandler.SendMessage)
+		messageGroup.DELETE("/:id/conversations/:conversationId/messages/:messageId", messageHandler.DeleteMessage)
+
+		messageGroup.GET("/:id/conversations/:conversationId", messageHandler.GetConversation)
+		messageGroup.POST("/:id/groups", messageHandler.CreateGroup)
+		messageGroup.PATCH("/:id/conversations/:conversationId", messageHandler.UpdateGroup)
+		messageGroup.POST("/:id/conversations/:conversationId/participants", messageHandler.AddParticipants)
+		messageGroup.DELETE("/:id/conversations/:conversationId/participants/:otherUserId", messageHandler.RemoveParticipant)
+		messageGroup.PATCH("/:id/conversations/:conversationId/participants/:otherUserId/role", messageHandler.UpdateRole)
+		messageGroup.POST("/:id/conversations/:conversationId/leave", messageHandler.LeaveGroup)
+		messageGroup.PATCH("/:id/conversations/:conversationId/read", messageHandler.MarkAsRead)
+	}
+
+	storyGroup := v1.Group("/story")
+	{
+		storyGroup.Use(validateToken)
+		storyGroup.POST("/:id", storyHandler.CreateStory)
+		storyGroup.GET("/:id/tray", storyHandler.GetTray)
+		storyGroup.GET("/:id/user/:otherUserId", storyHandler.GetUserStories)
+		storyGroup.DELETE("/:id/stories/:storyId", storyHandler.DeleteStory)
+		storyGroup.POST("/:id/stories/:storyId/view", storyHandler.ViewStory)
+		storyGroup.GET("/:id/stories/:storyId/viewers", storyHandler.GetViewers)
+
+		storyGroup.POST("/:id/highlights", storyHandler.CreateHighlight)
+		storyGroup.PUT("/:id/highlights/order", storyHandler.ReorderHighlights)
+		storyGroup.PATCH("/:id/highlights/:highlightId", storyHandler.UpdateHighlight)
+		storyGroup.DELETE("/:id/highlights/:highlightId", storyHandler.DeleteHighlight)
 	}
 }